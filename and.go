@@ -30,7 +30,48 @@ func (a And) Validate() (err error) {
 func (a And) String() string {
 	var childValues []string
 	for _, c := range a {
-		childValues = append(childValues, c.String())
+		// `,` binds tighter than `||`, so disjunctions need to be grouped, including those printed
+		// by other selectors, e.g. a double negation of an `Or`.
+		value := c.String()
+		if isDisjunction(value) {
+			childValues = append(childValues, "("+value+")")
+			continue
+		}
+		childValues = append(childValues, value)
 	}
 	return strings.Join(childValues, ", ")
 }
+
+// Children returns the selectors in the clause.
+func (a And) Children() []Selector {
+	return a
+}
+
+// WithChildren returns a clause of the given selectors, or nil if there are none.
+func (a And) WithChildren(children []Selector) Selector {
+	if len(children) == 0 {
+		return nil
+	}
+	return And(children)
+}
+
+// isDisjunction returns if a printed selector has a `||` outside of any parentheses.
+func isDisjunction(value string) bool {
+	if !strings.Contains(value, "||") {
+		return false
+	}
+	var depth int
+	for _, token := range Lex(value, Options{Dialect: DialectExtended}) {
+		switch token.Kind {
+		case KindOpenParens:
+			depth++
+		case KindCloseParens:
+			depth--
+		case KindOr:
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
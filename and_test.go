@@ -24,3 +24,31 @@ func TestAnd(t *testing.T) {
 
 	assert.Equal("foo == far, moo == lar", selector.String())
 }
+
+func TestAndStringGroupsDisjunctions(t *testing.T) {
+	assert := assert.New(t)
+
+	or := Or{Equals{Key: "a", Value: "x"}, Equals{Key: "b", Value: "y"}}
+	selectors := []Selector{
+		And{Not{Selector: Not{Selector: or}}, HasKey("z")},
+		And{Fold{Selector: or}, HasKey("z")},
+		And{or, HasKey("z")},
+		And{Not{Selector: or}, HasKey("z")},
+		And{Equals{Key: "a", Value: "x || y"}, HasKey("z")},
+	}
+	labels := []Labels{
+		{"a": "x"},
+		{"a": "x", "z": ""},
+		{"b": "y", "z": ""},
+		{"z": ""},
+	}
+	for _, s := range selectors {
+		parsed, err := ParseWithOptions(s.String(), Options{Dialect: DialectExtended})
+		assert.Nil(err, s.String())
+		for _, l := range labels {
+			assert.Equal(s.Matches(l), parsed.Matches(l), s.String(), " ", l)
+		}
+	}
+	assert.Equal("(a == x || b == y), z", And{Not{Selector: Not{Selector: or}}, HasKey("z")}.String())
+	assert.Equal(`a == "x || y", z`, selectors[4].String())
+}
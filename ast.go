@@ -0,0 +1,88 @@
+package selector
+
+// Span is a half open range of byte offsets into the source, `[Start, End)`.
+type Span struct {
+	Start, End int
+}
+
+// Len returns the length of the span in bytes.
+func (s Span) Len() int {
+	return s.End - s.Start
+}
+
+// Contains returns if a byte offset is within the span.
+func (s Span) Contains(offset int) bool {
+	return offset >= s.Start && offset < s.End
+}
+
+// Node is a selector in a syntax tree, with the spans of the source it was parsed from.
+//
+// For requirements `Key`, `Operator` and `Values` are the spans of each part; `Operator` is the `!`
+// of a `!key` requirement, and empty at the end of the key for a bare `key`. `Values` has one span
+// per value, i.e. per member of an `in` set, including any quotes.
+// For combinations (`And`, `Or` and negated groups) `Children` are the nodes of the combined selectors;
+// a negated group's `Operator` is its `!`.
+type Node struct {
+	Selector Selector
+	Span     Span
+	Key      Span
+	Operator Span
+	Values   []Span
+	Children []*Node
+
+	source string
+}
+
+// Source returns the text of the source the node was parsed from.
+func (n *Node) Source() string {
+	return n.source[n.Span.Start:n.Span.End]
+}
+
+// Text returns the text of a span of the node's source, e.g. `node.Text(node.Key)`.
+func (n *Node) Text(span Span) string {
+	return n.source[span.Start:span.End]
+}
+
+// At returns the innermost node whose span contains the byte offset, or nil if none does.
+func (n *Node) At(offset int) *Node {
+	if !n.Span.Contains(offset) {
+		return nil
+	}
+	for _, child := range n.Children {
+		if found := child.At(offset); found != nil {
+			return found
+		}
+	}
+	return n
+}
+
+// AST is a parsed selector and the syntax tree it was parsed from.
+type AST struct {
+	// Root is the syntax tree; its selectors are not wrapped for case folding.
+	Root *Node
+	// Selector is the parsed selector, as returned by `ParseWithOptions`.
+	Selector Selector
+
+	source string
+}
+
+// Source returns the original selector text.
+func (a *AST) Source() string {
+	return a.source
+}
+
+// ParseAST parses a selector with the given options, recording the source span of every
+// requirement, key, operator and value.
+func ParseAST(query string, opts Options) (*AST, error) {
+	p := NewParser(query, opts)
+	p.tree = true
+	selector, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	root := p.node
+	if root == nil { // an allowed empty selector
+		root = &Node{Selector: selector, Span: Span{0, len(query)}, source: query}
+	}
+	return &AST{Root: root, Selector: p.fold(selector), source: query}, nil
+}
@@ -0,0 +1,135 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestParseAST(t *testing.T) {
+	assert := assert.New(t)
+
+	query := " x == a, !y, z in (b, c), w "
+	ast, err := ParseAST(query, Options{})
+	assert.Nil(err)
+	assert.Equal(query, ast.Source())
+	assert.Equal("x == a, !y, z in (b, c), w", ast.Root.Source())
+
+	expected, _ := Parse(query)
+	assert.Equal(expected, ast.Selector)
+	assert.Equal(expected, ast.Root.Selector)
+
+	assert.Len(ast.Root.Children, 4)
+	equals := ast.Root.Children[0]
+	assert.Equal("x == a", equals.Source())
+	assert.Equal("x", equals.Text(equals.Key))
+	assert.Equal("==", equals.Text(equals.Operator))
+	assert.Len(equals.Values, 1)
+	assert.Equal("a", equals.Text(equals.Values[0]))
+
+	notHasKey := ast.Root.Children[1]
+	assert.Equal("!y", notHasKey.Source())
+	assert.Equal("!", notHasKey.Text(notHasKey.Operator))
+	assert.Equal("y", notHasKey.Text(notHasKey.Key))
+
+	in := ast.Root.Children[2]
+	assert.Equal("z in (b, c)", in.Source())
+	assert.Equal("in", in.Text(in.Operator))
+	assert.Len(in.Values, 2)
+	assert.Equal("b", in.Text(in.Values[0]))
+	assert.Equal("c", in.Text(in.Values[1]))
+
+	hasKey := ast.Root.Children[3]
+	assert.Equal("w", hasKey.Source())
+	assert.Equal(0, hasKey.Operator.Len())
+	assert.Equal(hasKey.Key.End, hasKey.Operator.Start)
+}
+
+func TestParseASTSingle(t *testing.T) {
+	assert := assert.New(t)
+
+	ast, err := ParseAST("x notin (a,b)", Options{})
+	assert.Nil(err)
+	assert.Empty(ast.Root.Children)
+	assert.Equal(NotIn{Key: "x", Values: []string{"a", "b"}}, ast.Root.Selector)
+	assert.Equal(Span{0, 13}, ast.Root.Span)
+
+	ast, err = ParseAST("x in (a,b  ), y", Options{})
+	assert.Nil(err)
+	in := ast.Root.Children[0]
+	assert.Equal("b", in.Text(in.Values[1]))
+	assert.Equal("x in (a,b  )", in.Source())
+}
+
+func TestParseASTExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	query := `app =~ "web-.*" || !(env == prod, tier in ('a b', c)), x`
+	ast, err := ParseAST(query, Options{Dialect: DialectExtended})
+	assert.Nil(err)
+
+	root := ast.Root
+	assert.Equal(query, root.Source())
+	_, isOr := root.Selector.(Or)
+	assert.True(isOr)
+	assert.Len(root.Children, 2)
+
+	matches := root.Children[0]
+	assert.Equal(`app =~ "web-.*"`, matches.Source())
+	assert.Equal(`"web-.*"`, matches.Text(matches.Values[0]))
+
+	conjunction := root.Children[1]
+	assert.Equal(`!(env == prod, tier in ('a b', c)), x`, conjunction.Source())
+	assert.Len(conjunction.Children, 2)
+
+	not := conjunction.Children[0]
+	assert.Equal(`!(env == prod, tier in ('a b', c))`, not.Source())
+	assert.Equal("!", not.Text(not.Operator))
+	assert.Len(not.Children, 1)
+	group := not.Children[0]
+	assert.Equal(`(env == prod, tier in ('a b', c))`, group.Source())
+	in := group.Children[1]
+	assert.Equal(`'a b'`, in.Text(in.Values[0]))
+
+	assert.Equal(in, root.At(len(`app =~ "web-.*" || !(env == prod, tier in ('a`)))
+	assert.Equal("x", root.At(len(query)-1).Source())
+	assert.Nil(root.At(len(query)))
+}
+
+func TestParseASTFlattens(t *testing.T) {
+	assert := assert.New(t)
+
+	ast, err := ParseAST("(a, b), c || d || (e || f)", Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	assert.Len(ast.Root.Children, 4)
+	assert.Len(ast.Root.Children[0].Children, 3)
+	assert.Equal("(a, b), c", ast.Root.Children[0].Source())
+	assert.Equal("f", ast.Root.Children[3].Source())
+}
+
+func TestParseASTOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	ast, err := ParseAST(" ", Options{AllowEmpty: true})
+	assert.Nil(err)
+	assert.Equal(And{}, ast.Root.Selector)
+
+	ast, err = ParseAST("x == A", Options{FoldCase: true})
+	assert.Nil(err)
+	assert.Equal(Equals{Key: "x", Value: "A"}, ast.Root.Selector)
+	assert.True(ast.Selector.Matches(Labels{"x": "a"}))
+
+	_, err = ParseAST("x ==", Options{})
+	assert.Nil(err)
+	_, err = ParseAST("x in (", Options{})
+	assert.NotNil(err)
+}
+
+func TestSpan(t *testing.T) {
+	assert := assert.New(t)
+
+	span := Span{2, 5}
+	assert.Equal(3, span.Len())
+	assert.True(span.Contains(2))
+	assert.False(span.Contains(5))
+}
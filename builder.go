@@ -0,0 +1,113 @@
+package selector
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Builder builds a selector fluently, e.g. `Key("app").In("web", "api").And(Key("env").NotEquals("dev"))`.
+// Each requirement is validated as it is added, and the errors are returned together by `Selector()`.
+// The selector built is identical to parsing its `String()` form; extended requirements such as
+// `GreaterThan` need the extended dialect to parse.
+type Builder struct {
+	selector Selector
+	errs     []error
+}
+
+// Selector returns the built selector, or the validation errors of every invalid requirement.
+// An empty builder returns an empty `And`, which matches everything.
+func (b Builder) Selector() (Selector, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	if b.selector == nil {
+		return And{}, nil
+	}
+	return b.selector, nil
+}
+
+// String returns the string form of the built selector.
+func (b Builder) String() string {
+	if b.selector == nil {
+		return ""
+	}
+	return b.selector.String()
+}
+
+// And returns a builder for the conjunction of this builder and others.
+func (b Builder) And(others ...Builder) Builder {
+	for _, other := range others {
+		b = b.combine(other, func(current, next Selector) Selector {
+			return liftAnd(current, next)
+		})
+	}
+	return b
+}
+
+// Or returns a builder for the disjunction of this builder and others.
+func (b Builder) Or(others ...Builder) Builder {
+	for _, other := range others {
+		b = b.combine(other, func(current, next Selector) Selector {
+			return liftOr(current, next)
+		})
+	}
+	return b
+}
+
+// Not returns a builder for the inverse of this builder, simplified as `Negate` does.
+func (b Builder) Not() Builder {
+	if b.selector != nil {
+		b.selector = Negate(b.selector)
+	}
+	return b
+}
+
+// combine combines two builders' selectors and errors.
+func (b Builder) combine(other Builder, combine func(current, next Selector) Selector) Builder {
+	errs := make([]error, 0, len(b.errs)+len(other.errs))
+	errs = append(append(errs, b.errs...), other.errs...)
+	switch {
+	case other.selector == nil:
+		return Builder{selector: b.selector, errs: errs}
+	case b.selector == nil:
+		return Builder{selector: other.selector, errs: errs}
+	}
+	return Builder{selector: combine(b.selector, other.selector), errs: errs}
+}
+
+// requirement returns a builder for a single requirement, validating it.
+func requirement(s Selector, err error) Builder {
+	if err == nil {
+		err = s.Validate()
+	}
+	if err != nil {
+		return Builder{errs: []error{fmt.Errorf("%s: %w", s.String(), err)}}
+	}
+	return Builder{selector: s}
+}
+
+// liftAnd combines selectors into an `And`, flattening conjunctions as the parser does.
+func liftAnd(current, next Selector) Selector {
+	output := And{}
+	for _, s := range []Selector{current, next} {
+		if typed, isAnd := s.(And); isAnd {
+			output = append(output, typed...)
+			continue
+		}
+		output = append(output, s)
+	}
+	return output
+}
+
+// liftOr combines selectors into an `Or`, flattening disjunctions as the parser does.
+func liftOr(current, next Selector) Selector {
+	output := Or{}
+	for _, s := range []Selector{current, next} {
+		if typed, isOr := s.(Or); isOr {
+			output = append(output, typed...)
+			continue
+		}
+		output = append(output, s)
+	}
+	return output
+}
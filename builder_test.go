@@ -0,0 +1,82 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := Key("app").In("web", "api").And(Key("env").NotEquals("dev")).Selector()
+	assert.Nil(err)
+	assert.Equal(And{In{Key: "app", Values: []string{"web", "api"}}, NotEquals{Key: "env", Value: "dev"}}, selector)
+
+	parsed, err := Parse(selector.String())
+	assert.Nil(err)
+	assert.Equal(parsed, selector)
+}
+
+func TestBuilderMatchesParser(t *testing.T) {
+	assert := assert.New(t)
+
+	builders := []Builder{
+		Key("x").Exists(),
+		Key("x").Exists().And(Key("y").DoesNotExist(), Key("z").Equals("a")),
+		Key("x").Exists().And(Key("y").Exists()).And(Key("z").Exists().And(Key("w").Exists())),
+		Key("x").In().And(Key("y").NotIn("a", "b")),
+		Key("x").Equals("a").Or(Key("y").Equals("b")).And(Key("z").Exists()),
+		Key("x").Equals("a").And(Key("y").Equals("b")).Or(Key("z").Exists(), Key("w").Exists()),
+		Key("x").Equals("a").Or(Key("y").Exists().Or(Key("z").Exists())),
+		Key("x").Equals("a").Not(),
+		Key("x").In("a").Not().And(Key("y").Exists()),
+		Key("x").Equals("a").And(Key("y").Exists()).Not().And(Key("z").Exists()),
+		Key("x").Equals("a").Or(Key("y").Exists()).Not(),
+		Key("x").Equals("a").Not().Not(),
+		Key("x").GreaterThan("4").And(Key("y").LessThanOrEqual("-1.5"), Key("z").GreaterThanOrEqual("0"), Key("w").LessThan("2")),
+		Key("x").Like("web-*", "api-?").And(Key("y").Like("a b")),
+		Key("x").Matches("web-[0-9]+").And(Key("y").NotMatches("a|b")),
+		Key("x").Equals("").And(Key("y").In("", "d")),
+		Key("x").Like("a*").Not(),
+	}
+	for _, builder := range builders {
+		selector, err := builder.Selector()
+		assert.Nil(err, builder.String())
+		parsed, err := ParseWithOptions(selector.String(), Options{Dialect: DialectExtended})
+		assert.Nil(err, selector.String())
+		assert.Equal(parsed, selector, selector.String())
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	builder := Key("-app").Equals("web").And(Key("env").In("a b"), Key("cpu").GreaterThan("lots"), Key("name").Matches("("), Key("ok").Exists())
+	selector, err := builder.Selector()
+	assert.Nil(selector)
+	assert.NotNil(err)
+	assert.True(errors.Is(err, ErrKeyInvalidCharacter))
+	assert.True(errors.Is(err, ErrValueNotNumeric))
+	assert.True(errors.Is(err, ErrInvalidPattern))
+	assert.Contains(err.Error(), "-app == web: ")
+	assert.Contains(err.Error(), `env in ("a b"): `)
+	assert.Equal("ok", builder.String())
+
+	_, err = builder.Not().Or(Key("x").Exists()).Selector()
+	assert.NotNil(err)
+}
+
+func TestBuilderEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := Builder{}.Selector()
+	assert.Nil(err)
+	assert.Equal(And{}, selector)
+	assert.Equal("", Builder{}.Not().String())
+
+	selector, err = Builder{}.And(Key("x").Exists()).Selector()
+	assert.Nil(err)
+	assert.Equal(HasKey("x"), selector)
+}
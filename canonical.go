@@ -0,0 +1,191 @@
+package selector
+
+import "sort"
+
+// Canonical returns a normalized string for a selector, so selectors that differ only in
+// the order of their requirements, repeated requirements, or the order of their values print the same.
+// Requirements are flattened, deduplicated and sorted by key and operator, with combinations such as
+// disjunctions last, and the values of `in`, `notin` and `like` are deduplicated and sorted.
+// Operators use the spellings from `String()`, e.g. `=` prints as `==`.
+//
+// Matching behavior that has no syntax is kept distinct: an `In` that requires its key is written as
+// `key, key in (...)`, which matches the same label sets under either semantics, and case folded selectors
+// are marked as `fold(...)`, or `foldkeys(...)` if keys are folded too. Canonical strings with folds don't parse.
+func Canonical(s Selector) string {
+	if s == nil {
+		return ""
+	}
+	return canonicalize(s).String()
+}
+
+// canonicalize returns the canonical form of a selector.
+func canonicalize(s Selector) Selector {
+	switch typed := s.(type) {
+	case And:
+		var children []Selector
+		for _, child := range typed {
+			canonical := canonicalize(child)
+			if nested, isAnd := canonical.(And); isAnd {
+				children = append(children, nested...)
+				continue
+			}
+			children = append(children, canonical)
+		}
+		children = sortSelectors(children)
+		if len(children) == 1 {
+			return children[0]
+		}
+		return And(children)
+	case Or:
+		var children []Selector
+		for _, child := range typed {
+			canonical := canonicalize(child)
+			if nested, isOr := canonical.(Or); isOr {
+				children = append(children, nested...)
+				continue
+			}
+			children = append(children, canonical)
+		}
+		children = sortSelectors(children)
+		if len(children) == 1 {
+			return children[0]
+		}
+		return Or(children)
+	case Not:
+		if typed.Selector == nil {
+			return typed
+		}
+		if negated, isSimplified := negate(typed.Selector); isSimplified {
+			if _, isNot := negated.(Not); !isNot {
+				return canonicalize(negated)
+			}
+		}
+		return Not{Selector: canonicalize(typed.Selector)}
+	case Fold:
+		if typed.Selector == nil {
+			return typed
+		}
+		return canonicalFold{Selector: canonicalize(typed.Selector), Keys: typed.Keys}
+	case In:
+		if typed.RequireKey {
+			return And{HasKey(typed.Key), In{Key: typed.Key, Values: sortValues(typed.Values), rules: typed.rules}}
+		}
+		return In{Key: typed.Key, Values: sortValues(typed.Values), rules: typed.rules}
+	case NotIn:
+		return NotIn{Key: typed.Key, Values: sortValues(typed.Values), rules: typed.rules}
+	case Like:
+		return Like{Key: typed.Key, Patterns: sortValues(typed.Patterns)}
+	}
+	return s
+}
+
+// canonicalFold is a case folded selector in canonical form, which is marked when printed
+// so it doesn't print the same as the selector it folds.
+type canonicalFold Fold
+
+// Matches returns the selector result.
+func (f canonicalFold) Matches(labels Labels) bool {
+	return Fold(f).Matches(labels)
+}
+
+// Validate validates the folded selector.
+func (f canonicalFold) Validate() error {
+	return Fold(f).Validate()
+}
+
+// String returns the folded selector marked with `fold(...)` or `foldkeys(...)`.
+func (f canonicalFold) String() string {
+	if f.Keys {
+		return "foldkeys(" + f.Selector.String() + ")"
+	}
+	return "fold(" + f.Selector.String() + ")"
+}
+
+// sortSelectors sorts canonical selectors by key, operator and string, removing duplicates.
+func sortSelectors(selectors []Selector) []Selector {
+	type sortable struct {
+		selector     Selector
+		combination  bool
+		key, value   string
+		operatorRank int
+	}
+	items := make([]sortable, len(selectors))
+	for index, s := range selectors {
+		key, rank := selectorKey(s)
+		items[index] = sortable{selector: s, combination: rank < 0, key: key, operatorRank: rank, value: s.String()}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.combination != b.combination {
+			return b.combination
+		}
+		if a.key != b.key {
+			return a.key < b.key
+		}
+		if a.operatorRank != b.operatorRank {
+			return a.operatorRank < b.operatorRank
+		}
+		return a.value < b.value
+	})
+
+	output := make([]Selector, 0, len(items))
+	for index, item := range items {
+		if index > 0 && item.value == items[index-1].value && item.key == items[index-1].key && item.operatorRank == items[index-1].operatorRank {
+			continue
+		}
+		output = append(output, item.selector)
+	}
+	return output
+}
+
+// selectorKey returns the key of a requirement and the rank of its operator,
+// or a negative rank for combinations and unknown selector types.
+func selectorKey(s Selector) (key string, rank int) {
+	switch typed := s.(type) {
+	case HasKey:
+		return string(typed), 0
+	case NotHasKey:
+		return string(typed), 1
+	case Equals:
+		return typed.Key, 2
+	case NotEquals:
+		return typed.Key, 3
+	case In:
+		return typed.Key, 4
+	case NotIn:
+		return typed.Key, 5
+	case GreaterThan:
+		return typed.Key, 6
+	case GreaterThanOrEqual:
+		return typed.Key, 7
+	case LessThan:
+		return typed.Key, 8
+	case LessThanOrEqual:
+		return typed.Key, 9
+	case Like:
+		return typed.Key, 10
+	case Matches:
+		return typed.Key, 11
+	case NotMatches:
+		return typed.Key, 12
+	}
+	return "", -1
+}
+
+// sortValues returns a sorted copy of the values without duplicates.
+func sortValues(values []string) []string {
+	if len(values) == 0 {
+		return values
+	}
+	sorted := make([]string, len(values))
+	copy(sorted, values)
+	sort.Strings(sorted)
+
+	output := sorted[:1]
+	for _, value := range sorted[1:] {
+		if value != output[len(output)-1] {
+			output = append(output, value)
+		}
+	}
+	return output
+}
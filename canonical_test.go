@@ -0,0 +1,109 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestCanonical(t *testing.T) {
+	assert := assert.New(t)
+
+	a, err := Parse("z in (c, b, c), x=a, !y, x")
+	assert.Nil(err)
+	b, err := Parse("x, !y,x == a,z in (b,c),  x==a")
+	assert.Nil(err)
+	assert.NotEqual(a.String(), b.String())
+	assert.Equal("x, x == a, !y, z in (b, c)", Canonical(a))
+	assert.Equal(Canonical(a), Canonical(b))
+}
+
+func TestCanonicalOperatorOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := And{
+		NotIn{Key: "x", Values: []string{"b", "a"}},
+		In{Key: "x", Values: []string{"b"}},
+		NotEquals{Key: "x", Value: "c"},
+		Equals{Key: "x", Value: "d"},
+		NotHasKey("x"),
+		HasKey("x"),
+	}
+	assert.Equal("x, !x, x == d, x != c, x in (b), x notin (a, b)", Canonical(selector))
+}
+
+func TestCanonicalExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	a, err := ParseWithOptions("b like (web-*, api-*) || a > 4, (c || a > 4)", Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	b, err := ParseWithOptions("a>4 or c or b ~= (api-*, web-*, web-*)", Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	assert.Equal("a > 4 || b like (api-*, web-*) || c", Canonical(b))
+
+	assert.Equal("a > 4, (b like (api-*, web-*) || a > 4, (a > 4 || c))", Canonical(And{a, GreaterThan{Key: "a", Value: "4"}}))
+}
+
+func TestCanonicalNot(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("x == a", Canonical(Not{Selector: Not{Selector: Equals{Key: "x", Value: "a"}}}))
+	assert.Equal("x, x notin (a, b)", Canonical(Not{Selector: In{Key: "x", Values: []string{"b", "a", "b"}}}))
+	assert.Equal("!(x, y == b)", Canonical(Not{Selector: And{Equals{Key: "y", Value: "b"}, HasKey("x")}}))
+}
+
+func TestCanonicalSingle(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", Canonical(nil))
+	assert.Equal("", Canonical(And{}))
+	assert.Equal("x", Canonical(And{HasKey("x"), HasKey("x")}))
+	assert.Equal("x in ()", Canonical(In{Key: "x"}))
+}
+
+func TestCanonicalDoesNotModify(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := And{In{Key: "x", Values: []string{"b", "a"}}, HasKey("a")}
+	Canonical(selector)
+	assert.Equal([]string{"b", "a"}, selector[0].(In).Values)
+	assert.Equal(HasKey("a"), selector[1])
+}
+
+func TestCanonicalRequireKey(t *testing.T) {
+	assert := assert.New(t)
+
+	strict := In{Key: "x", Values: []string{"b", "a"}, RequireKey: true}
+	compatible := In{Key: "x", Values: []string{"b", "a"}}
+	assert.Equal("x, x in (a, b)", Canonical(strict))
+	assert.Equal("x in (a, b)", Canonical(compatible))
+	assert.NotEqual(Canonical(strict), Canonical(compatible))
+
+	// the strict requirement isn't lost as a duplicate of the default one.
+	both := And{compatible, strict}
+	canonical := canonicalize(both)
+	for _, labels := range []Labels{{}, {"x": "a"}, {"x": "c"}} {
+		assert.Equal(both.Matches(labels), canonical.Matches(labels), labels)
+	}
+	assert.Equal("x, x in (a, b)", Canonical(both))
+
+	parsed, err := Parse(Canonical(strict))
+	assert.Nil(err)
+	assert.True(Equivalent(strict, parsed))
+}
+
+func TestCanonicalFold(t *testing.T) {
+	assert := assert.New(t)
+
+	equals := Equals{Key: "x", Value: "a"}
+	assert.Equal("fold(x == a)", Canonical(Fold{Selector: equals}))
+	assert.Equal("foldkeys(x == a)", Canonical(Fold{Selector: equals, Keys: true}))
+	assert.NotEqual(Canonical(Fold{Selector: equals}), Canonical(equals))
+
+	both := And{Fold{Selector: equals}, equals}
+	assert.Equal("x == a, fold(x == a)", Canonical(both))
+	canonical := canonicalize(both)
+	for _, labels := range []Labels{{"x": "a"}, {"x": "A"}} {
+		assert.Equal(both.Matches(labels), canonical.Matches(labels), labels)
+	}
+}
@@ -0,0 +1,133 @@
+package selector
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+	k8s "k8s.io/apimachinery/pkg/labels"
+)
+
+var (
+	conformanceKeys   = []string{"app", "env", "example.com/tier"}
+	conformanceValues = []string{"web", "api", "db", ""}
+)
+
+// generateRequirement returns a random requirement in the kubernetes grammar.
+func generateRequirement(r *rand.Rand) string {
+	key := conformanceKeys[r.Intn(len(conformanceKeys))]
+	value := conformanceValues[r.Intn(len(conformanceValues))]
+	switch r.Intn(7) {
+	case 0:
+		return key
+	case 1:
+		return "!" + key
+	case 2:
+		return fmt.Sprintf("%s=%s", key, value)
+	case 3:
+		return fmt.Sprintf("%s == %s", key, value)
+	case 4:
+		return fmt.Sprintf("%s != %s", key, value)
+	case 5:
+		return fmt.Sprintf("%s in (%s)", key, strings.Join(generateValueSet(r), ", "))
+	default:
+		return fmt.Sprintf("%s notin (%s)", key, strings.Join(generateValueSet(r), ","))
+	}
+}
+
+// generateValueSet returns a random non-empty set of non-empty values.
+func generateValueSet(r *rand.Rand) []string {
+	var values []string
+	for _, value := range conformanceValues[:len(conformanceValues)-1] {
+		if r.Intn(2) == 0 {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		values = append(values, conformanceValues[0])
+	}
+	return values
+}
+
+// generateSelector returns a random selector of one to four requirements.
+func generateSelector(r *rand.Rand) string {
+	requirements := make([]string, r.Intn(4)+1)
+	for index := range requirements {
+		requirements[index] = generateRequirement(r)
+	}
+	return strings.Join(requirements, ",")
+}
+
+// generateLabels returns a random label set over the conformance keys.
+func generateLabels(r *rand.Rand) Labels {
+	labels := Labels{}
+	for _, key := range conformanceKeys {
+		if r.Intn(3) > 0 {
+			labels[key] = conformanceValues[r.Intn(len(conformanceValues))]
+		}
+	}
+	return labels
+}
+
+// hasInWithAbsentKey returns if the selector has an `in` requirement whose key is not in the labels.
+func hasInWithAbsentKey(selector Selector, labels Labels) bool {
+	requirements, isAnd := selector.(And)
+	if !isAnd {
+		requirements = And{selector}
+	}
+	for _, requirement := range requirements {
+		if typed, isIn := requirement.(In); isIn {
+			if _, hasKey := labels[typed.Key]; !hasKey {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestConformanceK8sStrict(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(1))
+	for index := 0; index < 2048; index++ {
+		query := generateSelector(r)
+		expected, err := k8s.Parse(query)
+		assert.Nil(err, query)
+		actual, err := ParseWithOptions(query, Options{Semantics: SemanticsK8sStrict})
+		assert.Nil(err, query)
+
+		for sample := 0; sample < 16; sample++ {
+			labels := generateLabels(r)
+			assert.Equal(expected.Matches(k8s.Set(labels)), actual.Matches(labels), query, " ", labels)
+		}
+	}
+}
+
+func TestConformanceCompatible(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(2))
+	var differences int
+	for index := 0; index < 2048; index++ {
+		query := generateSelector(r)
+		expected, err := k8s.Parse(query)
+		assert.Nil(err, query)
+		actual, err := Parse(query)
+		assert.Nil(err, query)
+
+		for sample := 0; sample < 16; sample++ {
+			labels := generateLabels(r)
+			expectedResult, actualResult := expected.Matches(k8s.Set(labels)), actual.Matches(labels)
+			if expectedResult == actualResult {
+				continue
+			}
+			differences++
+			// the only permitted difference is `in` matching label sets without the key.
+			assert.False(expectedResult, query, " ", labels)
+			assert.True(hasInWithAbsentKey(actual, labels), query, " ", labels)
+		}
+	}
+	assert.True(differences > 0, "the compatible semantics should differ from kubernetes")
+}
@@ -0,0 +1,39 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Contradiction explains why a selector can never match.
+type Contradiction struct {
+	// Key is the key the conflicting requirements are on.
+	Key string
+	// Requirements are a minimal set of requirements on the key that can't all match.
+	Requirements []Selector
+	// Reason describes the conflict.
+	Reason string
+}
+
+// String returns the reason.
+func (c *Contradiction) String() string {
+	return c.Reason
+}
+
+// newContradiction returns a contradiction for conflicting atoms on a key.
+func newContradiction(key string, atoms []atom) *Contradiction {
+	requirements := make([]Selector, len(atoms))
+	descriptions := make([]string, len(atoms))
+	for index, a := range atoms {
+		requirements[index] = a.requirement()
+		descriptions[index] = requirements[index].String()
+	}
+
+	var reason string
+	if len(descriptions) == 1 {
+		reason = fmt.Sprintf("%s never matches", descriptions[0])
+	} else {
+		reason = fmt.Sprintf("%s can't all match %q", strings.Join(descriptions, ", "), key)
+	}
+	return &Contradiction{Key: key, Requirements: requirements, Reason: reason}
+}
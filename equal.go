@@ -0,0 +1,91 @@
+package selector
+
+import (
+	"reflect"
+	"sort"
+)
+
+// EqualSelectors returns if two selectors have the same structure, ignoring the order of the children of
+// combinations and of the values of `in`, `notin` and `like`; repeated children and values are significant.
+// It compares selectors that can't be compared with `==` because they contain slices, e.g. `In`,
+// and ignores the dialect rules a selector's values were validated against.
+// Selector types from other packages are compared with `reflect.DeepEqual`.
+func EqualSelectors(a, b Selector) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	switch typed := a.(type) {
+	case And:
+		other, isAnd := b.(And)
+		return isAnd && equalChildren(typed, other)
+	case Or:
+		other, isOr := b.(Or)
+		return isOr && equalChildren(typed, other)
+	case Not:
+		other, isNot := b.(Not)
+		return isNot && EqualSelectors(typed.Selector, other.Selector)
+	case Fold:
+		other, isFold := b.(Fold)
+		return isFold && typed.Keys == other.Keys && EqualSelectors(typed.Selector, other.Selector)
+	case Equals:
+		other, isEquals := b.(Equals)
+		return isEquals && typed.Key == other.Key && typed.Value == other.Value
+	case NotEquals:
+		other, isNotEquals := b.(NotEquals)
+		return isNotEquals && typed.Key == other.Key && typed.Value == other.Value
+	case In:
+		other, isIn := b.(In)
+		return isIn && typed.Key == other.Key && typed.RequireKey == other.RequireKey && equalValues(typed.Values, other.Values)
+	case NotIn:
+		other, isNotIn := b.(NotIn)
+		return isNotIn && typed.Key == other.Key && equalValues(typed.Values, other.Values)
+	case Like:
+		other, isLike := b.(Like)
+		return isLike && typed.Key == other.Key && equalValues(typed.Patterns, other.Patterns)
+	case Matches:
+		other, isMatches := b.(Matches)
+		return isMatches && typed.Key == other.Key && typed.Pattern == other.Pattern
+	case NotMatches:
+		other, isNotMatches := b.(NotMatches)
+		return isNotMatches && typed.Key == other.Key && typed.Pattern == other.Pattern
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// equalChildren returns if each child in one list is equal to a different child in the other.
+func equalChildren(children, others []Selector) bool {
+	if len(children) != len(others) {
+		return false
+	}
+	used := make([]bool, len(others))
+	for _, child := range children {
+		found := false
+		for index, other := range others {
+			if !used[index] && EqualSelectors(child, other) {
+				used[index], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// equalValues returns if two lists have the same values in any order.
+func equalValues(values, others []string) bool {
+	if len(values) != len(others) {
+		return false
+	}
+	sorted := append([]string(nil), values...)
+	sortedOthers := append([]string(nil), others...)
+	sort.Strings(sorted)
+	sort.Strings(sortedOthers)
+	for index := range sorted {
+		if sorted[index] != sortedOthers[index] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestEqualSelectors(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"x in (a, b), y", "y, x in (b, a)", true},
+		{"x notin (a, b)", "x notin (b, a)", true},
+		{"x ~= (a*, b*)", "x ~= (b*, a*)", true},
+		{"x =~ a.*", "x =~ a.*", true},
+		{"x =~ a.*", "x =~ a.+", false},
+		{"x=a || y=b, z", "z, y=b || x=a", true},
+		{"!(x=a, y=b)", "!(y=b, x=a)", true},
+		{"x=a", "x in (a)", false},
+		{"x!=a, x!=b", "x notin (a, b)", false},
+		{"x in (a, a)", "x in (a)", false},
+		{"x, x", "x", false},
+		{"x, x, y", "x, y, y", false},
+	}
+	for _, c := range cases {
+		a, err := ParseWithOptions(c.a, Options{Dialect: DialectExtended})
+		assert.Nil(err, c.a)
+		b, err := ParseWithOptions(c.b, Options{Dialect: DialectExtended})
+		assert.Nil(err, c.b)
+		assert.Equal(c.equal, EqualSelectors(a, b), c.a, " == ", c.b)
+		assert.Equal(c.equal, EqualSelectors(b, a), c.b, " == ", c.a)
+	}
+}
+
+func TestEqualSelectorsRequireKey(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(EqualSelectors(In{Key: "x", Values: []string{"a"}}, In{Key: "x", Values: []string{"a"}, RequireKey: true}))
+	assert.False(EqualSelectors(Fold{Selector: HasKey("x")}, Fold{Selector: HasKey("x"), Keys: true}))
+	assert.True(EqualSelectors(Fold{Selector: HasKey("x")}, Fold{Selector: HasKey("x")}))
+	assert.True(EqualSelectors(nil, nil))
+	assert.False(EqualSelectors(HasKey("x"), nil))
+	assert.False(EqualSelectors(Not{}, Not{Selector: HasKey("x")}))
+}
+
+func TestEqualSelectorsCompiled(t *testing.T) {
+	assert := assert.New(t)
+
+	compiled, err := NewMatches("x", "a.*")
+	assert.Nil(err)
+	assert.True(EqualSelectors(compiled, Matches{Key: "x", Pattern: "a.*"}))
+}
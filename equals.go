@@ -5,6 +5,9 @@ import "fmt"
 // Equals returns if a key strictly equals a value.
 type Equals struct {
 	Key, Value string
+
+	// rules are the value rules of the dialect that parsed the selector, which `Validate` checks.
+	rules valueRules
 }
 
 // Matches returns the selector result.
@@ -21,11 +24,11 @@ func (e Equals) Validate() (err error) {
 	if err != nil {
 		return
 	}
-	err = CheckValue(e.Value)
+	err = e.rules.check(e.Value)
 	return
 }
 
 // String returns the string representation of the selector.
 func (e Equals) String() string {
-	return fmt.Sprintf("%s == %s", e.Key, e.Value)
+	return fmt.Sprintf("%s == %s", e.Key, quoteValue(e.Value))
 }
@@ -18,4 +18,5 @@ func TestEquals(t *testing.T) {
 	assert.False(Equals{Key: "foo", Value: "bar"}.Matches(valid))
 
 	assert.Equal("foo == bar", Equals{Key: "foo", Value: "bar"}.String())
+	assert.Equal(`foo == "bar, baz"`, Equals{Key: "foo", Value: "bar, baz"}.String())
 }
@@ -0,0 +1,18 @@
+package selector
+
+// Equivalent returns if two selectors match exactly the same label sets, e.g. `x=a` and `x in (a)` under
+// kubernetes semantics, or `x!=a,x!=b` and `x notin (a,b)`. It is decided by checking that each selector implies the other.
+//
+// Where that can't be decided exactly, e.g. for selector types from other packages or some combinations
+// of patterns, it falls back to `EqualSelectors`, so it never reports different selectors as equivalent.
+func Equivalent(a, b Selector) bool {
+	if EqualSelectors(a, b) {
+		return true
+	}
+	forward, err := Implies(a, b)
+	if err != nil || !forward {
+		return false
+	}
+	backward, err := Implies(b, a)
+	return err == nil && backward
+}
@@ -0,0 +1,57 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestEquivalent(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		a, b       string
+		equivalent bool
+	}{
+		{"x!=a,x!=b", "x notin (a,b)", true},
+		{"x in (a, b), y", "y, x in (b, a)", true},
+		{"x=a,x=b", "y, !y", true},
+		{"x=a || x=b", "x in (a, b), x", true},
+		{"!(x=a || y=b)", "x != a, y != b", true},
+		{"x, x=a", "x=a", true},
+		{"replicas > 4", "replicas > 4.0", true},
+		{"replicas >= 4", "replicas > 4", false},
+		{"x=a", "x in (a)", false},
+		{"x!=a", "x notin (a,b)", false},
+	}
+	for _, c := range cases {
+		a, err := ParseWithOptions(c.a, Options{Dialect: DialectExtended})
+		assert.Nil(err, c.a)
+		b, err := ParseWithOptions(c.b, Options{Dialect: DialectExtended})
+		assert.Nil(err, c.b)
+		assert.Equal(c.equivalent, Equivalent(a, b), c.a, " ~ ", c.b)
+		assert.Equal(c.equivalent, Equivalent(b, a), c.b, " ~ ", c.a)
+	}
+}
+
+func TestEquivalentK8sStrict(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := Options{Semantics: SemanticsK8sStrict}
+	a, _ := ParseWithOptions("x=a", opts)
+	b, _ := ParseWithOptions("x in (a)", opts)
+	assert.True(Equivalent(a, b))
+}
+
+func TestEquivalentUndecidable(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := Options{Dialect: DialectExtended}
+	a, _ := ParseWithOptions("x =~ web-[0-9]+", opts)
+	b, _ := ParseWithOptions("x =~ web-[0-9]+", opts)
+	assert.True(Equivalent(a, b))
+
+	b, _ = ParseWithOptions("x =~ web-[0-9][0-9]*", opts)
+	assert.False(Equivalent(a, b))
+	assert.True(Equivalent(Fold{Selector: HasKey("x")}, Fold{Selector: HasKey("x")}))
+}
@@ -0,0 +1,86 @@
+package selector
+
+import "fmt"
+
+// Example is a label set generated for a selector, and whether the selector matches it.
+type Example struct {
+	Name    string
+	Labels  Labels
+	Matches bool
+}
+
+// String returns the example's name and outcome, e.g. `app absent: matches`.
+func (e Example) String() string {
+	if e.Matches {
+		return e.Name + ": matches"
+	}
+	return e.Name + ": doesn't match"
+}
+
+// Examples returns label sets for table tests of a selector: a `Witness` and a `Counterexample` where they exist,
+// then for each key in the selector, the witness with the key missing, an empty value, each value the selector
+// lists for the key, and a value outside the list. Numeric comparisons add values beside their bounds, and patterns
+// values they match. Keys and values that don't pass `CheckKey` and `CheckValue` are left out.
+func Examples(s Selector) []Example {
+	var output []Example
+	base, err := Witness(s)
+	if err == nil {
+		output = append(output, Example{Name: "witness", Labels: base, Matches: true})
+	} else {
+		base = Labels{}
+	}
+	if counterexample, err := Counterexample(s); err == nil {
+		output = append(output, Example{Name: "counterexample", Labels: counterexample})
+	}
+
+	seen := map[string]bool{}
+	add := func(name string, labels Labels) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		output = append(output, Example{Name: name, Labels: labels, Matches: s.Matches(labels)})
+	}
+
+	var keys []string
+	atoms := map[string][]atom{}
+	Inspect(s, func(s Selector) bool {
+		if key, rank := selectorKey(s); rank >= 0 {
+			if _, hasKey := atoms[key]; !hasKey {
+				keys = append(keys, key)
+			}
+			atoms[key] = append(atoms[key], atom{selector: s, key: key})
+		}
+		return true
+	})
+	for _, key := range keys {
+		if CheckKey(key) != nil {
+			continue
+		}
+		absent := copyLabels(base)
+		delete(absent, key)
+		add(key+" absent", absent)
+		for _, value := range candidates(atoms[key]) {
+			if CheckValue(value) != nil {
+				continue
+			}
+			labels := copyLabels(base)
+			labels[key] = value
+			if value == "" {
+				add(fmt.Sprintf("%s=%q", key, value), labels)
+				continue
+			}
+			add(key+"="+value, labels)
+		}
+	}
+	return output
+}
+
+// copyLabels returns a copy of a label set.
+func copyLabels(labels Labels) Labels {
+	output := make(Labels, len(labels))
+	for key, value := range labels {
+		output[key] = value
+	}
+	return output
+}
@@ -0,0 +1,65 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestExamples(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := Parse("app in (web, api), env != prod")
+	assert.Nil(err)
+	var names []string
+	for _, example := range Examples(selector) {
+		names = append(names, example.String())
+		assert.Equal(selector.Matches(example.Labels), example.Matches)
+	}
+	assert.Equal([]string{
+		"witness: matches",
+		"counterexample: doesn't match",
+		"app absent: matches",
+		"app=web: matches",
+		"app=api: matches",
+		"app=0: doesn't match",
+		`app="": doesn't match`,
+		"app=x: doesn't match",
+		"env absent: matches",
+		"env=prod: doesn't match",
+		"env=0: matches",
+		`env="": matches`,
+		"env=x: matches",
+	}, names)
+}
+
+func TestExamplesKeyAbsent(t *testing.T) {
+	assert := assert.New(t)
+
+	// `in` matches absent keys by default, which the examples make visible.
+	selector, _ := Parse("app in (web), app")
+	examples := Examples(selector)
+	assert.Equal("witness", examples[0].Name)
+	assert.Equal(Labels{"app": "web"}, examples[0].Labels)
+	var absent *Example
+	for index := range examples {
+		if examples[index].Name == "app absent" {
+			absent = &examples[index]
+		}
+	}
+	assert.NotNil(absent)
+	assert.False(absent.Matches)
+
+	selector, _ = Parse("app in (web)")
+	assert.True(Examples(selector)[0].Matches)
+	assert.Equal(Labels{}, Examples(selector)[0].Labels)
+}
+
+func TestExamplesValid(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, _ := ParseWithOptions("replicas > -3, name ~= web-*", Options{Dialect: DialectExtended})
+	for _, example := range Examples(selector) {
+		assert.Nil(checkLabels(example.Labels), example.Name)
+	}
+}
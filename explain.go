@@ -0,0 +1,102 @@
+package selector
+
+// Explain evaluates a selector against a label set, reporting the outcome of each requirement and the label
+// value it saw, and marking the first failing requirement. A top level conjunction is listed as its requirements;
+// other combinations are listed as `all of`, `any of`, `not` or `case folded`, followed by their children.
+// Requirements within a `Fold` are evaluated with case folding, as the fold evaluates them.
+// A nil selector matches everything, and is reported as matching with no requirements.
+func Explain(s Selector, labels Labels) Report {
+	if s == nil {
+		return Report{Matches: true}
+	}
+	e := explainer{labels: labels}
+	var roots []int
+	if typed, isAnd := s.(And); isAnd {
+		for _, child := range typed {
+			roots = append(roots, e.explain(child, 0, false, false))
+		}
+	} else {
+		roots = append(roots, e.explain(s, 0, false, false))
+	}
+
+	report := Report{Selector: s.String(), Matches: s.Matches(labels), Requirements: e.outcomes}
+	if !report.Matches {
+		e.markFailure(roots)
+	}
+	return report
+}
+
+// explainer collects the outcomes of a selector tree.
+type explainer struct {
+	labels   Labels
+	outcomes []Outcome
+	children [][]int
+	descend  []bool
+}
+
+// explain adds the outcomes for a selector and its children, returning the index of the selector's outcome.
+func (e *explainer) explain(s Selector, depth int, folded, keys bool) int {
+	outcome := Outcome{Requirement: describe(s), Depth: depth}
+	if folded {
+		outcome.Matches = matchesFold(s, e.labels, keys)
+	} else {
+		outcome.Matches = s.Matches(e.labels)
+	}
+	if key, rank := selectorKey(s); rank >= 0 {
+		outcome.Key = key
+		outcome.Value, outcome.Present = lookupFold(e.labels, key, folded && keys)
+	}
+
+	index := len(e.outcomes)
+	e.outcomes = append(e.outcomes, outcome)
+	e.children = append(e.children, nil)
+	e.descend = append(e.descend, false)
+
+	switch typed := s.(type) {
+	case And:
+		e.descend[index] = true
+	case Fold:
+		e.descend[index] = true
+		folded, keys = true, keys || typed.Keys
+	}
+	if parent, isParent := s.(Parent); isParent {
+		for _, child := range parent.Children() {
+			childIndex := e.explain(child, depth+1, folded, keys)
+			e.children[index] = append(e.children[index], childIndex)
+		}
+	}
+	return index
+}
+
+// markFailure marks the first failing outcome, descending into conjunctions and folds to the requirement that failed.
+func (e *explainer) markFailure(indexes []int) {
+	for _, index := range indexes {
+		if e.outcomes[index].Matches {
+			continue
+		}
+		if e.descend[index] && len(e.children[index]) > 0 {
+			e.markFailure(e.children[index])
+			return
+		}
+		e.outcomes[index].FirstFailure = true
+		return
+	}
+}
+
+// describe returns the text for a selector's outcome.
+func describe(s Selector) string {
+	switch typed := s.(type) {
+	case And:
+		return "all of"
+	case Or:
+		return "any of"
+	case Not:
+		return "not"
+	case Fold:
+		if typed.Keys {
+			return "case folded keys and values"
+		}
+		return "case folded"
+	}
+	return s.String()
+}
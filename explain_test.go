@@ -0,0 +1,99 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestExplain(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := Parse("app in (web), env != prod, tier")
+	assert.Nil(err)
+	report := Explain(selector, Labels{"app": "api", "tier": ""})
+	assert.False(report.Matches)
+	assert.Equal("app in (web), env != prod, tier", report.Selector)
+	assert.Equal([]Outcome{
+		{Requirement: "app in (web)", Key: "app", Value: "api", Present: true, FirstFailure: true},
+		{Requirement: "env != prod", Key: "env", Matches: true},
+		{Requirement: "tier", Key: "tier", Present: true, Matches: true},
+	}, report.Requirements)
+
+	failure, hasFailure := report.FirstFailure()
+	assert.True(hasFailure)
+	assert.Equal("app in (web)", failure.Requirement)
+}
+
+func TestExplainMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, _ := Parse("app in (web)")
+	report := Explain(selector, Labels{})
+	assert.True(report.Matches)
+	assert.Len(report.Requirements, 1)
+	assert.True(report.Requirements[0].Matches)
+	assert.False(report.Requirements[0].Present)
+	_, hasFailure := report.FirstFailure()
+	assert.False(hasFailure)
+}
+
+func TestExplainNested(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseWithOptions("app, (env=prod || tier=db), !(team=a, region=eu)", Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	report := Explain(selector, Labels{"app": "web", "env": "dev", "team": "a", "region": "eu"})
+	assert.False(report.Matches)
+
+	var lines []string
+	for _, outcome := range report.Requirements {
+		lines = append(lines, outcome.String())
+	}
+	assert.Equal([]string{
+		`pass app (app="web")`,
+		"fail any of <- first failure",
+		`fail env == prod (env="dev")`,
+		"fail tier == db (tier absent)",
+		"fail not",
+		"pass all of",
+		`pass team == a (team="a")`,
+		`pass region == eu (region="eu")`,
+	}, lines)
+	assert.Equal(1, report.Requirements[5].Depth)
+	assert.Equal(2, report.Requirements[6].Depth)
+}
+
+func TestExplainDescendsIntoConjunctions(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := Or{And{HasKey("a"), And{HasKey("b"), HasKey("c")}}}
+	report := Explain(Not{Selector: Not{Selector: selector}}, Labels{"a": "", "b": ""})
+	failure, _ := report.FirstFailure()
+	assert.Equal("not", failure.Requirement)
+
+	report = Explain(And{HasKey("a"), And{HasKey("b"), HasKey("c")}}, Labels{"a": "", "b": ""})
+	failure, _ = report.FirstFailure()
+	assert.Equal("c", failure.Requirement)
+}
+
+func TestExplainFold(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseWithOptions("App == Web", Options{FoldCase: true, FoldKeys: true})
+	assert.Nil(err)
+	report := Explain(selector, Labels{"app": "WEB"})
+	assert.True(report.Matches)
+	assert.Equal("case folded keys and values", report.Requirements[0].Requirement)
+	assert.Equal(Outcome{Requirement: "App == Web", Depth: 1, Key: "App", Value: "WEB", Present: true, Matches: true}, report.Requirements[1])
+}
+
+func TestExplainNil(t *testing.T) {
+	assert := assert.New(t)
+
+	report := Explain(nil, Labels{"app": "web"})
+	assert.True(report.Matches)
+	assert.Empty(report.Requirements)
+	_, found := report.FirstFailure()
+	assert.False(found)
+}
@@ -0,0 +1,214 @@
+package selector
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Fold is a combination selector that matches its selector comparing values with unicode case folding,
+// e.g. `app == Web` matches `app=WEB`. If `Keys` is set label keys are also compared with case folding.
+//
+// The built in selector types are matched without allocating; other selector types are matched as normal.
+// Use `NewFold` to precompile case insensitive regular expressions.
+type Fold struct {
+	Selector Selector
+	Keys     bool
+}
+
+// NewFold returns a fold selector, compiling the regular expressions it contains to be case insensitive.
+func NewFold(selector Selector, keys bool) Fold {
+	return Fold{Selector: foldPatterns(selector), Keys: keys}
+}
+
+// Matches returns if the selector matches the labels with case folded comparisons.
+func (f Fold) Matches(labels Labels) bool {
+	return matchesFold(f.Selector, labels, f.Keys)
+}
+
+// Validate validates the folded selector.
+func (f Fold) Validate() error {
+	if f.Selector == nil {
+		return ErrInvalidSelector
+	}
+	return f.Selector.Validate()
+}
+
+// String returns a string representation for the selector.
+// Case folding is a parse option rather than syntax, so this is the folded selector's string.
+func (f Fold) String() string {
+	if f.Selector == nil {
+		return ""
+	}
+	return f.Selector.String()
+}
+
+// matchesFold evaluates a selector with case folded comparisons of values, and optionally keys.
+func matchesFold(s Selector, labels Labels, keys bool) bool {
+	switch typed := s.(type) {
+	case And:
+		for _, child := range typed {
+			if !matchesFold(child, labels, keys) {
+				return false
+			}
+		}
+		return true
+	case Or:
+		for _, child := range typed {
+			if matchesFold(child, labels, keys) {
+				return true
+			}
+		}
+		return false
+	case Not:
+		return !matchesFold(typed.Selector, labels, keys)
+	case Fold:
+		return matchesFold(typed.Selector, labels, keys || typed.Keys)
+	case HasKey:
+		_, hasKey := lookupFold(labels, string(typed), keys)
+		return hasKey
+	case NotHasKey:
+		_, hasKey := lookupFold(labels, string(typed), keys)
+		return !hasKey
+	case Equals:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		return hasValue && strings.EqualFold(value, typed.Value)
+	case NotEquals:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		return !hasValue || !strings.EqualFold(value, typed.Value)
+	case In:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		if !hasValue {
+			return !typed.RequireKey
+		}
+		return containsFold(typed.Values, value)
+	case NotIn:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		return !hasValue || !containsFold(typed.Values, value)
+	case Like:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		if !hasValue {
+			return false
+		}
+		for _, pattern := range typed.Patterns {
+			if globMatchCase(pattern, value, true) {
+				return true
+			}
+		}
+		return false
+	case Matches:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		compiled, err := foldRegexp(typed.Pattern, typed.compiled)
+		return hasValue && err == nil && compiled.MatchString(value)
+	case NotMatches:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		if !hasValue {
+			return true
+		}
+		compiled, err := foldRegexp(typed.Pattern, typed.compiled)
+		return err == nil && !compiled.MatchString(value)
+	case GreaterThan:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		result, ok := compareNumeric(value, typed.Value)
+		return hasValue && ok && result > 0
+	case GreaterThanOrEqual:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		result, ok := compareNumeric(value, typed.Value)
+		return hasValue && ok && result >= 0
+	case LessThan:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		result, ok := compareNumeric(value, typed.Value)
+		return hasValue && ok && result < 0
+	case LessThanOrEqual:
+		value, hasValue := lookupFold(labels, typed.Key, keys)
+		result, ok := compareNumeric(value, typed.Value)
+		return hasValue && ok && result <= 0
+	}
+	return s.Matches(labels)
+}
+
+// lookupFold returns the value for a key, optionally finding the key with case folding.
+// An exact match is preferred; otherwise the lowest sorting key that folds to the same key is used.
+func lookupFold(labels Labels, key string, keys bool) (value string, hasValue bool) {
+	value, hasValue = labels[key]
+	if hasValue || !keys {
+		return
+	}
+	var found string
+	for labelKey, labelValue := range labels {
+		if strings.EqualFold(labelKey, key) && (!hasValue || labelKey < found) {
+			found, value, hasValue = labelKey, labelValue, true
+		}
+	}
+	return
+}
+
+// containsFold returns if any of the values equals the value with case folding.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// foldPatterns returns a copy of the selector with the regular expressions compiled case insensitive.
+func foldPatterns(s Selector) Selector {
+	switch typed := s.(type) {
+	case And:
+		folded := make(And, len(typed))
+		for index, child := range typed {
+			folded[index] = foldPatterns(child)
+		}
+		return folded
+	case Or:
+		folded := make(Or, len(typed))
+		for index, child := range typed {
+			folded[index] = foldPatterns(child)
+		}
+		return folded
+	case Not:
+		return Not{Selector: foldPatterns(typed.Selector)}
+	case Fold:
+		return Fold{Selector: foldPatterns(typed.Selector), Keys: typed.Keys}
+	case Matches:
+		if compiled, err := foldRegexp(typed.Pattern, typed.compiled); err == nil {
+			typed.compiled = compiled
+		}
+		return typed
+	case NotMatches:
+		if compiled, err := foldRegexp(typed.Pattern, typed.compiled); err == nil {
+			typed.compiled = compiled
+		}
+		return typed
+	}
+	return s
+}
+
+// foldRegexp returns the case insensitive form of a pattern, reusing the compiled pattern if it already is.
+func foldRegexp(pattern string, compiled *regexp.Regexp) (*regexp.Regexp, error) {
+	if compiled != nil && strings.HasPrefix(compiled.String(), caseInsensitiveFlag) {
+		return compiled, nil
+	}
+	return compilePatternFlags(pattern, caseInsensitiveFlag)
+}
+
+// Children returns the folded selector.
+func (f Fold) Children() []Selector {
+	if f.Selector == nil {
+		return nil
+	}
+	return []Selector{f.Selector}
+}
+
+// WithChildren returns the given selector folded in the same way; several selectors are combined in an `And`,
+// and nil is returned if there are none.
+func (f Fold) WithChildren(children []Selector) Selector {
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		return Fold{Selector: children[0], Keys: f.Keys}
+	}
+	return Fold{Selector: And(children), Keys: f.Keys}
+}
@@ -0,0 +1,80 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestFold(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := NewFold(And{
+		Equals{Key: "app", Value: "Web"},
+		NotEquals{Key: "env", Value: "DEV"},
+		In{Key: "tier", Values: []string{"Front", "back"}},
+		NotIn{Key: "zone", Values: []string{"EU"}},
+	}, false)
+
+	assert.True(selector.Matches(Labels{"app": "WEB", "env": "prod", "tier": "FRONT", "zone": "us"}))
+	assert.False(selector.Matches(Labels{"app": "WEB", "env": "dev", "tier": "FRONT", "zone": "us"}))
+	assert.False(selector.Matches(Labels{"app": "WEB", "env": "prod", "tier": "middle", "zone": "us"}))
+	assert.False(selector.Matches(Labels{"app": "WEB", "env": "prod", "tier": "FRONT", "zone": "eu"}))
+	assert.False(selector.Matches(Labels{"APP": "web"}), "keys are not folded unless enabled")
+
+	assert.Nil(selector.Validate())
+	assert.NotNil(Fold{}.Validate())
+	assert.Equal("app == Web, env != DEV, tier in (Front, back), zone notin (EU)", selector.String())
+}
+
+func TestFoldUnicode(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := Fold{Selector: Equals{Key: "name", Value: "ΣΊΣΥΦΟΣ"}}
+	assert.True(selector.Matches(Labels{"name": "σίσυφος"}))
+	assert.True(selector.Matches(Labels{"name": "ΣΊΣΥΦΟς"}), "final sigma folds with sigma")
+
+	selector = Fold{Selector: Equals{Key: "temp", Value: "k"}}
+	assert.True(selector.Matches(Labels{"temp": "K"}), "kelvin sign folds with k")
+}
+
+func TestFoldKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := NewFold(And{HasKey("app"), NotHasKey("env"), GreaterThan{Key: "gen", Value: "3"}}, true)
+	assert.True(selector.Matches(Labels{"APP": "web", "Gen": "4"}))
+	assert.False(selector.Matches(Labels{"APP": "web", "ENV": "prod", "Gen": "4"}))
+	assert.False(selector.Matches(Labels{"APP": "web", "GEN": "3"}))
+
+	value, hasValue := lookupFold(Labels{"App": "b", "APP": "a"}, "app", true)
+	assert.True(hasValue)
+	assert.Equal("a", value, "the lowest sorting key should be used")
+
+	value, hasValue = lookupFold(Labels{"App": "b", "app": "c"}, "app", true)
+	assert.True(hasValue)
+	assert.Equal("c", value, "exact keys should be preferred")
+}
+
+func TestFoldPatterns(t *testing.T) {
+	assert := assert.New(t)
+
+	matches, err := NewMatches("app", "web-[a-z]+")
+	assert.Nil(err)
+	notMatches, err := NewNotMatches("env", "dev")
+	assert.Nil(err)
+
+	selector := NewFold(Or{
+		And{matches, notMatches},
+		Like{Key: "app", Patterns: []string{"API-[a-c]*"}},
+	}, false)
+
+	assert.True(selector.Matches(Labels{"app": "WEB-Canary", "env": "prod"}))
+	assert.False(selector.Matches(Labels{"app": "WEB-Canary", "env": "DEV"}))
+	assert.True(selector.Matches(Labels{"app": "api-B1"}))
+	assert.False(selector.Matches(Labels{"app": "api-d1"}))
+
+	unprepared := Fold{Selector: Matches{Key: "app", Pattern: "web"}}
+	assert.True(unprepared.Matches(Labels{"app": "WEB"}))
+
+	assert.False(matches.Matches(Labels{"app": "WEB-Canary"}), "folding should not modify the original selector")
+}
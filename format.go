@@ -0,0 +1,57 @@
+package selector
+
+import "strings"
+
+// Format reformats a selector query with normalized spacing, keeping the order of requirements
+// and the spelling of operators and values, e.g. `x=a,y  in(b,c)` formats as `x = a, y in (b, c)`.
+// Redundant commas in value sets and a trailing comma are removed.
+// It returns an error if the query does not parse with the given options.
+func Format(query string, opts Options) (string, error) {
+	if _, err := ParseWithOptions(query, opts); err != nil {
+		return "", err
+	}
+
+	tokens := Lex(query, opts)
+	var output strings.Builder
+	var previous *Token
+	for index := range tokens {
+		token := &tokens[index]
+		if token.Kind == KindComma && isRedundantComma(previous, tokens[index+1:]) {
+			continue
+		}
+		if previous != nil && needsSpace(previous.Kind, token.Kind) {
+			output.WriteRune(Space)
+		}
+		output.WriteString(token.Text)
+		previous = token
+	}
+	return output.String(), nil
+}
+
+// isRedundantComma returns if a comma can be removed without changing the selector;
+// a leading, repeated or trailing comma in a value set, or a trailing comma at the end of the query.
+func isRedundantComma(previous *Token, remaining []Token) bool {
+	if previous == nil || previous.Kind == KindComma || previous.Kind == KindOpenParens {
+		return true
+	}
+	if len(remaining) == 0 {
+		return true
+	}
+	next := remaining[0].Kind
+	return next == KindComma || next == KindCloseParens
+}
+
+// needsSpace returns if a space separates two adjacent tokens.
+func needsSpace(previous, current TokenKind) bool {
+	switch current {
+	case KindComma, KindCloseParens:
+		return false
+	case KindOperator, KindOr:
+		return true
+	}
+	switch previous {
+	case KindOperator, KindComma, KindOr:
+		return true
+	}
+	return false
+}
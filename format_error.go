@@ -0,0 +1,91 @@
+package selector
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Caret is the rune used to underline the offending text in `FormatError`.
+const Caret = rune('^')
+
+// FormatError renders an error returned by `Parse`, `CheckKey` or `CheckValue` in the style of a
+// compiler diagnostic; the selector text, a caret line under the offending span, and a one line explanation:
+//
+//	foo == bar, moo !~ lar
+//	                ^^
+//	invalid operator: found "!~", expected "!="
+//
+// If the error is a `*ParseError` the input and span are taken from the error, otherwise `input`
+// is treated as the offending text (i.e. the key or value passed to `CheckKey` or `CheckValue`)
+// and is underlined in full.
+func FormatError(input string, err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var typed *ParseError
+	if errors.As(err, &typed) {
+		return formatCaret(typed.Input, typed.Offset, typed.End(), typed.Message())
+	}
+	return formatCaret(input, 0, len(input), err.Error())
+}
+
+// formatCaret renders the line of input containing `start` with a caret line under [start, end).
+func formatCaret(input string, start, end int, message string) string {
+	if start > len(input) {
+		start = len(input)
+	}
+	lineStart := strings.LastIndexByte(input[:start], byte(NewLine)) + 1
+	lineEnd := len(input)
+	if index := strings.IndexByte(input[start:], byte(NewLine)); index >= 0 {
+		lineEnd = start + index
+	}
+	if end > lineEnd {
+		end = lineEnd
+	}
+	line := strings.TrimSuffix(input[lineStart:lineEnd], string(CarriageReturn))
+
+	var caret []rune
+	var ch rune
+	var width int
+	for pos := lineStart; pos < start; pos += width {
+		ch, width = utf8.DecodeRuneInString(input[pos:])
+		if ch == Tab {
+			caret = append(caret, Tab)
+			continue
+		}
+		for column := 0; column < runeWidth(ch); column++ {
+			caret = append(caret, Space)
+		}
+	}
+
+	underlined := 0
+	for pos := start; pos < end; pos += width {
+		ch, width = utf8.DecodeRuneInString(input[pos:])
+		for column := 0; column < runeWidth(ch); column++ {
+			caret = append(caret, Caret)
+			underlined++
+		}
+	}
+	if underlined == 0 {
+		caret = append(caret, Caret)
+	}
+
+	return line + string(NewLine) + string(caret) + string(NewLine) + message
+}
+
+// runeWidth returns the number of terminal columns a rune occupies.
+func runeWidth(ch rune) int {
+	if unicode.Is(unicode.Mn, ch) || unicode.Is(unicode.Me, ch) {
+		return 0
+	}
+	if unicode.In(ch, unicode.Hangul, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+		return 2
+	}
+	if ch >= 0xFF01 && ch <= 0xFF60 { // fullwidth forms
+		return 2
+	}
+	return 1
+}
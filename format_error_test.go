@@ -0,0 +1,69 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestFormatErrorParse(t *testing.T) {
+	assert := assert.New(t)
+
+	query := "foo == bar, moo !~ lar"
+	_, err := Parse(query)
+	assert.NotNil(err)
+	expected := "foo == bar, moo !~ lar\n" +
+		"                ^^\n" +
+		`invalid operator: found "!~", expected "!="`
+	assert.Equal(expected, FormatError(query, err))
+}
+
+func TestFormatErrorEndOfInput(t *testing.T) {
+	assert := assert.New(t)
+
+	query := "x in (a, b"
+	_, err := Parse(query)
+	expected := "x in (a, b\n" +
+		"          ^\n" +
+		`invalid selector: found end of input, expected one of "," or ")"`
+	assert.Equal(expected, FormatError(query, err))
+}
+
+func TestFormatErrorValidation(t *testing.T) {
+	assert := assert.New(t)
+
+	query := "foo == bar, -moo"
+	_, err := Parse(query)
+	assert.True(errors.Is(err, ErrKeyInvalidCharacter))
+	expected := "foo == bar, -moo\n" +
+		"            ^^^^\n" +
+		ErrKeyInvalidCharacter.Error() + `: found "-moo"`
+	assert.Equal(expected, FormatError(query, err))
+}
+
+func TestFormatErrorCheckKey(t *testing.T) {
+	assert := assert.New(t)
+
+	key := "foo_"
+	err := CheckKey(key)
+	assert.NotNil(err)
+	assert.Equal("foo_\n^^^^\n"+ErrKeyInvalidCharacter.Error(), FormatError(key, err))
+	assert.Equal("", FormatError(key, nil))
+}
+
+func TestFormatErrorWideRunes(t *testing.T) {
+	assert := assert.New(t)
+
+	query := "함=수 수"
+	_, err := Parse(query)
+	assert.Equal("함=수 수\n      ^^\n"+`invalid selector: found "수", expected one of "," or end of input`, FormatError(query, err))
+}
+
+func TestFormatErrorMultiline(t *testing.T) {
+	assert := assert.New(t)
+
+	query := "foo == bar,\n\tmoo !~ lar"
+	_, err := Parse(query)
+	assert.Equal("\tmoo !~ lar\n\t    ^^\n"+`invalid operator: found "!~", expected "!="`, FormatError(query, err))
+}
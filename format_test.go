@@ -0,0 +1,40 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	formatted, err := Format("x=a,y  in(c,b),  !z,w==,v", Options{})
+	assert.Nil(err)
+	assert.Equal("x = a, y in (c, b), !z, w ==, v", formatted)
+
+	formatted, err = Format("  x in (,a,,b,) ,", Options{})
+	assert.Nil(err)
+	assert.Equal("x in (a, b)", formatted)
+
+	expected, _ := Parse("  x in (,a,,b,) ,")
+	actual, err := Parse(formatted)
+	assert.Nil(err)
+	assert.Equal(expected, actual)
+}
+
+func TestFormatExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	formatted, err := Format(`!(x=~"a b"||y>=4),(z like(a*,'b c')or w)`, Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	assert.Equal(`!(x =~ "a b" || y >= 4), (z like (a*, 'b c') or w)`, formatted)
+}
+
+func TestFormatInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Format("x in (a", Options{})
+	assert.True(errors.Is(err, ErrInvalidSelector))
+}
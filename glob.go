@@ -0,0 +1,189 @@
+package selector
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// CheckGlob returns if a glob pattern is well formed.
+// Patterns support `*` (any run of runes), `?` (any single rune), character classes such as
+// `[abc]`, `[a-z]` and `[!a-z]` (or `[^a-z]`), and backslash escapes.
+func CheckGlob(pattern string) error {
+	var ch rune
+	var width int
+	for pos := 0; pos < len(pattern); pos += width {
+		ch, width = utf8.DecodeRuneInString(pattern[pos:])
+		switch ch {
+		case BackSlash:
+			if pos+width == len(pattern) {
+				return ErrInvalidGlob
+			}
+			_, escaped := utf8.DecodeRuneInString(pattern[pos+width:])
+			width += escaped
+		case OpenBracket:
+			next, ok := skipGlobClass(pattern, pos)
+			if !ok {
+				return ErrInvalidGlob
+			}
+			width = next - pos
+		}
+	}
+	return nil
+}
+
+// globMatch returns if the value matches the glob pattern in its entirety.
+// Malformed character classes never match.
+func globMatch(pattern, value string) bool {
+	return globMatchCase(pattern, value, false)
+}
+
+// globMatchCase returns if the value matches the glob pattern in its entirety,
+// optionally comparing runes with unicode case folding.
+func globMatchCase(pattern, value string, fold bool) bool {
+	var p, v int
+	// the pattern and value positions to resume from when backtracking to the last star.
+	starP, starV := -1, -1
+
+	var ch, vch rune
+	var width, vwidth int
+	for v < len(value) {
+		if p < len(pattern) {
+			ch, width = utf8.DecodeRuneInString(pattern[p:])
+			vch, vwidth = utf8.DecodeRuneInString(value[v:])
+			switch ch {
+			case Star:
+				p += width
+				starP, starV = p, v
+				continue
+			case QuestionMark:
+				p += width
+				v += vwidth
+				continue
+			case OpenBracket:
+				if matched, next, ok := matchGlobClassCase(pattern, p, vch, fold); ok && matched {
+					p = next
+					v += vwidth
+					continue
+				}
+			case BackSlash:
+				if p+width < len(pattern) {
+					escaped, escapedWidth := utf8.DecodeRuneInString(pattern[p+width:])
+					if equalRune(escaped, vch, fold) {
+						p += width + escapedWidth
+						v += vwidth
+						continue
+					}
+				}
+			default:
+				if equalRune(ch, vch, fold) {
+					p += width
+					v += vwidth
+					continue
+				}
+			}
+		}
+
+		// backtrack; let the last star consume one more rune.
+		if starP < 0 {
+			return false
+		}
+		_, vwidth = utf8.DecodeRuneInString(value[starV:])
+		starV += vwidth
+		p, v = starP, starV
+	}
+
+	for p < len(pattern) && rune(pattern[p]) == Star {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// matchGlobClassCase matches a rune, or optionally any rune it case folds to, against the character class
+// starting at `start`, returning if it matched, the position after the class, and if the class was well formed.
+func matchGlobClassCase(pattern string, start int, ch rune, fold bool) (matched bool, next int, ok bool) {
+	pos := start + 1 // skip the open bracket
+	negated := false
+	if pos < len(pattern) && (rune(pattern[pos]) == Bang || rune(pattern[pos]) == Caret) {
+		negated = true
+		pos++
+	}
+
+	var low, high rune
+	var width int
+	first := true
+	for pos < len(pattern) {
+		low, width = utf8.DecodeRuneInString(pattern[pos:])
+		if low == CloseBracket && !first {
+			return matched != negated, pos + width, true
+		}
+		first = false
+		if low == BackSlash {
+			pos += width
+			if pos == len(pattern) {
+				return
+			}
+			low, width = utf8.DecodeRuneInString(pattern[pos:])
+		}
+		pos += width
+
+		high = low
+		if pos+1 < len(pattern) && rune(pattern[pos]) == Dash && rune(pattern[pos+1]) != CloseBracket {
+			pos++
+			high, width = utf8.DecodeRuneInString(pattern[pos:])
+			if high == BackSlash {
+				pos += width
+				if pos == len(pattern) {
+					return
+				}
+				high, width = utf8.DecodeRuneInString(pattern[pos:])
+			}
+			pos += width
+			if high < low {
+				return
+			}
+		}
+		if inRange(ch, low, high, fold) {
+			matched = true
+		}
+	}
+	return
+}
+
+// skipGlobClass returns the position after the character class starting at `start`,
+// and if the class was well formed.
+func skipGlobClass(pattern string, start int) (int, bool) {
+	_, next, ok := matchGlobClassCase(pattern, start, utf8.RuneError, false)
+	return next, ok
+}
+
+// equalRune returns if two runes are equal, optionally under unicode simple case folding.
+func equalRune(a, b rune, fold bool) bool {
+	if a == b {
+		return true
+	}
+	if !fold {
+		return false
+	}
+	for folded := unicode.SimpleFold(a); folded != a; folded = unicode.SimpleFold(folded) {
+		if folded == b {
+			return true
+		}
+	}
+	return false
+}
+
+// inRange returns if a rune, or optionally any rune it case folds to, is within [low, high].
+func inRange(ch, low, high rune, fold bool) bool {
+	if low <= ch && ch <= high {
+		return true
+	}
+	if !fold {
+		return false
+	}
+	for folded := unicode.SimpleFold(ch); folded != ch; folded = unicode.SimpleFold(folded) {
+		if low <= folded && folded <= high {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestGlobMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		Pattern, Value string
+		Expected       bool
+	}{
+		{"", "", true},
+		{"", "a", false},
+		{"*", "", true},
+		{"*", "anything", true},
+		{"web-*", "web-1", true},
+		{"web-*", "web-", true},
+		{"web-*", "api-1", false},
+		{"*-canary", "web-canary", true},
+		{"*-canary", "web-canary-2", false},
+		{"w*b*1", "web-api-1", true},
+		{"w*b*1", "web-api-2", false},
+		{"web-?", "web-1", true},
+		{"web-?", "web-12", false},
+		{"web-??", "web-12", true},
+		{"?", "함", true},
+		{"함*", "함수", true},
+		{"web-[0-9]", "web-7", true},
+		{"web-[0-9]", "web-a", false},
+		{"web-[!0-9]", "web-a", true},
+		{"web-[^0-9]", "web-7", false},
+		{"[abc]pi", "api", true},
+		{"[]]", "]", true},
+		{"[a-]", "-", true},
+		{`web-\*`, "web-*", true},
+		{`web-\*`, "web-1", false},
+		{"a*a*a*a*b", "aaaaaaaaaaaaaaaaaaaa", false},
+		{"**", "abc", true},
+		{"web-[", "web-[", false},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(testCase.Expected, globMatch(testCase.Pattern, testCase.Value), testCase.Pattern, " ", testCase.Value)
+	}
+}
+
+func TestGlobMatchFold(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(globMatchCase("WEB-*", "web-1", true))
+	assert.True(globMatchCase("web-[A-C]", "web-b", true))
+	assert.True(globMatchCase("web-[!a-c]", "web-D", true))
+	assert.False(globMatchCase("web-[!a-c]", "web-B", true))
+	assert.True(globMatchCase(`\Σ?`, "σς", true))
+	assert.False(globMatchCase("WEB-*", "web-1", false))
+}
+
+func TestCheckGlob(t *testing.T) {
+	assert := assert.New(t)
+
+	good := []string{"", "*", "web-*", "web-?", "[a-z]*", "[!a-z]", "[]a]", `\*`, `[\]]`}
+	for _, pattern := range good {
+		assert.Nil(CheckGlob(pattern), pattern)
+	}
+	bad := []string{"[", "web-[a-z", `web-\`, "[z-a]", `[a-\`}
+	for _, pattern := range bad {
+		assert.Equal(ErrInvalidGlob, CheckGlob(pattern), pattern)
+	}
+}
+
+func BenchmarkGlobMatch(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if !globMatch("web-*-[0-9]?", "web-canary-12") {
+			b.Fail()
+		}
+	}
+}
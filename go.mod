@@ -0,0 +1,7 @@
+module github.com/blendlabs/go-selector
+
+go 1.20
+
+require k8s.io/apimachinery v0.17.4
+
+require k8s.io/klog v1.0.0 // indirect
@@ -0,0 +1,63 @@
+package selector
+
+import "fmt"
+
+// GreaterThan returns if a key's value is numerically greater than a value.
+// Both values must be integers or decimals; the selector does not match if the key is absent or not numeric.
+type GreaterThan struct {
+	Key, Value string
+}
+
+// Matches returns the selector result.
+func (gt GreaterThan) Matches(labels Labels) bool {
+	if value, hasValue := labels[gt.Key]; hasValue {
+		result, ok := compareNumeric(value, gt.Value)
+		return ok && result > 0
+	}
+	return false
+}
+
+// Validate validates the selector.
+func (gt GreaterThan) Validate() (err error) {
+	err = CheckKey(gt.Key)
+	if err != nil {
+		return
+	}
+	err = CheckNumber(gt.Value)
+	return
+}
+
+// String returns a string representation of the selector.
+func (gt GreaterThan) String() string {
+	return fmt.Sprintf("%s > %s", gt.Key, quoteValue(gt.Value))
+}
+
+// GreaterThanOrEqual returns if a key's value is numerically greater than or equal to a value.
+// Both values must be integers or decimals; the selector does not match if the key is absent or not numeric.
+type GreaterThanOrEqual struct {
+	Key, Value string
+}
+
+// Matches returns the selector result.
+func (gte GreaterThanOrEqual) Matches(labels Labels) bool {
+	if value, hasValue := labels[gte.Key]; hasValue {
+		result, ok := compareNumeric(value, gte.Value)
+		return ok && result >= 0
+	}
+	return false
+}
+
+// Validate validates the selector.
+func (gte GreaterThanOrEqual) Validate() (err error) {
+	err = CheckKey(gte.Key)
+	if err != nil {
+		return
+	}
+	err = CheckNumber(gte.Value)
+	return
+}
+
+// String returns a string representation of the selector.
+func (gte GreaterThanOrEqual) String() string {
+	return fmt.Sprintf("%s >= %s", gte.Key, quoteValue(gte.Value))
+}
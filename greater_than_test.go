@@ -0,0 +1,35 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestGreaterThan(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := GreaterThan{Key: "cpu-gen", Value: "4"}
+	assert.True(selector.Matches(Labels{"cpu-gen": "5"}))
+	assert.True(selector.Matches(Labels{"cpu-gen": "4.5"}))
+	assert.False(selector.Matches(Labels{"cpu-gen": "4"}))
+	assert.False(selector.Matches(Labels{"cpu-gen": "three"}))
+	assert.False(selector.Matches(Labels{"foo": "5"}))
+
+	assert.Nil(selector.Validate())
+	assert.Equal(ErrValueNotNumeric, GreaterThan{Key: "cpu-gen", Value: "four"}.Validate())
+	assert.Equal("cpu-gen > 4", selector.String())
+}
+
+func TestGreaterThanOrEqual(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := GreaterThanOrEqual{Key: "cpu-gen", Value: "4"}
+	assert.True(selector.Matches(Labels{"cpu-gen": "5"}))
+	assert.True(selector.Matches(Labels{"cpu-gen": "4.0"}))
+	assert.False(selector.Matches(Labels{"cpu-gen": "3.9"}))
+	assert.False(selector.Matches(Labels{"foo": "5"}))
+
+	assert.Nil(selector.Validate())
+	assert.Equal("cpu-gen >= 4", selector.String())
+}
@@ -0,0 +1,21 @@
+package selector
+
+// Implies returns if every label set that matches `a` also matches `b`, i.e. if `a` is a narrower scope than `b`.
+// It is decided by checking that `a, !(b)` can't match any label set.
+//
+// The answer is exact for the package's requirement types and `And`, `Or` and `Not` combinations of them.
+// Selector types from other packages and `Fold` return `ErrUnsupportedSelector`; combinations of glob or
+// regular expression patterns the solver can't decide, or that expand too far, return `ErrUndecidable`.
+func Implies(a, b Selector) (bool, error) {
+	result, _, _, err := solve(And{a, Not{Selector: b}})
+	if err != nil {
+		return false, err
+	}
+	switch result {
+	case unsatisfiable:
+		return true, nil
+	case satisfiable:
+		return false, nil
+	}
+	return false, ErrUndecidable
+}
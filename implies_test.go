@@ -0,0 +1,126 @@
+package selector
+
+import (
+	"math/rand"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestImplies(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		a, b    string
+		implies bool
+	}{
+		{"team=a, env=prod", "team=a", true},
+		{"team=a", "team=a, env=prod", false},
+		{"team=a", "team in (a, b)", true},
+		{"team in (a, b)", "team=a", false},
+		{"team=a", "team", true},
+		{"team", "team=a", false},
+		{"team=a", "team != b", true},
+		{"!team", "team notin (a)", true},
+		{"!team", "team != a", true},
+		{"team notin (a, b)", "team != a", true},
+		{"team != a", "team notin (a, b)", false},
+		{"team=a || team=b", "team in (a, b)", true},
+		{"team in (a, b), team", "team=a || team=b", true},
+		{"team=a", "team=a || env=prod", true},
+		{"(team=a || team=b), env=prod", "env", true},
+		{"!(team=a || env=prod)", "team != a", true},
+		{"", "team", false},
+		{"team", "", true},
+		{"team=a, team=b", "env=prod", true},
+	}
+	for _, c := range cases {
+		a, err := ParseWithOptions(c.a, Options{Dialect: DialectExtended, AllowEmpty: true})
+		assert.Nil(err, c.a)
+		b, err := ParseWithOptions(c.b, Options{Dialect: DialectExtended, AllowEmpty: true})
+		assert.Nil(err, c.b)
+		implies, err := Implies(a, b)
+		assert.Nil(err, c.a, " => ", c.b)
+		assert.Equal(c.implies, implies, c.a, " => ", c.b)
+	}
+}
+
+func TestImpliesKeyAbsent(t *testing.T) {
+	assert := assert.New(t)
+
+	// by default `in` also matches label sets without the key, so it doesn't imply the key exists.
+	a, _ := Parse("team in (a)")
+	b, _ := Parse("team")
+	implies, err := Implies(a, b)
+	assert.Nil(err)
+	assert.False(implies)
+
+	a, _ = ParseWithOptions("team in (a)", Options{Semantics: SemanticsK8sStrict})
+	implies, err = Implies(a, b)
+	assert.Nil(err)
+	assert.True(implies)
+}
+
+func TestImpliesExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		a, b    string
+		implies bool
+	}{
+		{"replicas > 4", "replicas >= 4", true},
+		{"replicas >= 4", "replicas > 4", false},
+		{"replicas > 4", "replicas > 3.5", true},
+		{"replicas > 3.5", "replicas > 4", false},
+		{"replicas == 4", "replicas <= 4.0", true},
+		{"replicas < 2, replicas > 1", "replicas != 1", true},
+		{"name ~= web-*", "name", true},
+		{"name == web-1", "name ~= web-*", true},
+		{"name ~= web-*", "name == web-1", false},
+		{"name =~ ^web-[0-9]+$", "name != web-a", true},
+	}
+	for _, c := range cases {
+		a, err := ParseWithOptions(c.a, Options{Dialect: DialectExtended})
+		assert.Nil(err, c.a)
+		b, err := ParseWithOptions(c.b, Options{Dialect: DialectExtended})
+		assert.Nil(err, c.b)
+		implies, err := Implies(a, b)
+		assert.Nil(err, c.a, " => ", c.b)
+		assert.Equal(c.implies, implies, c.a, " => ", c.b)
+	}
+}
+
+func TestImpliesErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	a, _ := ParseWithOptions("name =~ web-[0-9]+", Options{Dialect: DialectExtended})
+	b, _ := ParseWithOptions("name =~ web-.*", Options{Dialect: DialectExtended})
+	_, err := Implies(a, b)
+	assert.True(err == ErrUndecidable)
+
+	_, err = Implies(Fold{Selector: HasKey("a")}, HasKey("a"))
+	assert.NotNil(err)
+}
+
+func TestImpliesMatchesLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(6))
+	for index := 0; index < 512; index++ {
+		a, err := Parse(generateSelector(r))
+		assert.Nil(err)
+		b, err := Parse(generateSelector(r))
+		assert.Nil(err)
+		implies, err := Implies(a, b)
+		assert.Nil(err)
+		if !implies {
+			continue
+		}
+		for sample := 0; sample < 64; sample++ {
+			labels := generateLabels(r)
+			if a.Matches(labels) {
+				assert.True(b.Matches(labels), a, " => ", b, " ", labels)
+			}
+		}
+	}
+}
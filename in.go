@@ -1,14 +1,17 @@
 package selector
 
-import (
-	"fmt"
-	"strings"
-)
+import "fmt"
 
 // In returns if a key matches a set of values.
+// By default `In` also matches if the key is absent; set `RequireKey` to match
+// the kubernetes semantics, where the key must be present.
 type In struct {
-	Key    string
-	Values []string
+	Key        string
+	Values     []string
+	RequireKey bool
+
+	// rules are the value rules of the dialect that parsed the selector, which `Validate` checks.
+	rules valueRules
 }
 
 // Matches returns the selector result.
@@ -24,7 +27,7 @@ func (i In) Matches(labels Labels) bool {
 		}
 		return false
 	}
-	return true
+	return !i.RequireKey
 }
 
 // Validate validates the selector.
@@ -34,7 +37,7 @@ func (i In) Validate() (err error) {
 		return
 	}
 	for _, v := range i.Values {
-		err = CheckValue(v)
+		err = i.rules.check(v)
 		if err != nil {
 			return
 		}
@@ -43,6 +46,8 @@ func (i In) Validate() (err error) {
 }
 
 // String returns a string representation of the selector.
+// `RequireKey` is not part of the syntax, so a strict `In` prints the same as the default one; the string only
+// parses back to the same selector with the semantics it was parsed with, e.g. `SemanticsK8sStrict` for a strict `In`.
 func (i In) String() string {
-	return fmt.Sprintf("%s in (%s)", i.Key, strings.Join(i.Values, ", "))
+	return fmt.Sprintf("%s in (%s)", i.Key, quoteSetValues(i.Values))
 }
@@ -33,4 +33,37 @@ func TestIn(t *testing.T) {
 	assert.False(selector.Matches(invalid))
 
 	assert.Equal("foo in (bar, far)", selector.String())
+	assert.Equal(`foo in (bar, "", "b (c)")`, In{Key: "foo", Values: []string{"bar", "", "b (c)"}}.String())
+}
+
+func TestInRequireKey(t *testing.T) {
+	assert := assert.New(t)
+
+	missing := Labels{"moo": "lar"}
+	selector := In{Key: "foo", Values: []string{"bar"}, RequireKey: true}
+	assert.False(selector.Matches(missing))
+	assert.True(selector.Matches(Labels{"foo": "bar"}))
+	assert.False(selector.Matches(Labels{"foo": "far"}))
+	assert.Equal("foo in (bar)", selector.String())
+
+	assert.Equal(NotIn{Key: "foo", Values: []string{"bar"}}, Negate(selector))
+	assert.False(NewFold(selector, true).Matches(missing))
+	assert.True(NewFold(selector, true).Matches(Labels{"FOO": "BAR"}))
+}
+
+func TestInRequireKeyRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	// `RequireKey` isn't printed, so the string parses back to a strict `In` only with strict semantics.
+	strict := In{Key: "foo", Values: []string{"bar"}, RequireKey: true}
+	compatible := In{Key: "foo", Values: []string{"bar"}}
+	assert.Equal(compatible.String(), strict.String())
+
+	parsed, err := ParseWithOptions(strict.String(), Options{Semantics: SemanticsK8sStrict})
+	assert.Nil(err)
+	assert.Equal(strict, parsed)
+
+	parsed, err = Parse(strict.String())
+	assert.Nil(err)
+	assert.Equal(compatible, parsed)
 }
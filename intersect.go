@@ -0,0 +1,7 @@
+package selector
+
+// Intersect returns a selector matching the label sets both selectors match,
+// their conjunction simplified as `Simplify` does, e.g. `x in (a, b)` and `x in (b, c)` give `x in (b)`.
+func Intersect(a, b Selector) Selector {
+	return Simplify(liftAnd(a, b))
+}
@@ -0,0 +1,54 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestIntersect(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		a, b     string
+		expected string
+	}{
+		{"tenant=a", "env=prod", "tenant == a, env == prod"},
+		{"tenant=a, env", "env=prod", "tenant == a, env == prod"},
+		{"x in (a, b)", "x in (b, c)", "x in (b)"},
+		{"x != a", "x notin (b)", "x notin (a, b)"},
+		{"tenant=a", "tenant=a", "tenant == a"},
+		{"tenant=a", "", "tenant == a"},
+	}
+	for _, c := range cases {
+		a, err := ParseWithOptions(c.a, Options{AllowEmpty: true})
+		assert.Nil(err, c.a)
+		b, err := ParseWithOptions(c.b, Options{AllowEmpty: true})
+		assert.Nil(err, c.b)
+		assert.Equal(c.expected, Intersect(a, b).String(), c.a, " & ", c.b)
+	}
+}
+
+func TestIntersectStaysDisjunctive(t *testing.T) {
+	assert := assert.New(t)
+
+	a, _ := ParseWithOptions("x=a || y=b", Options{Dialect: DialectExtended})
+	b, _ := Parse("z=c")
+	intersection := Intersect(a, b)
+	assert.Equal("(x == a || y == b), z == c", intersection.String())
+
+	reparsed, err := ParseWithOptions(intersection.String(), Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	assert.Equal(intersection.String(), reparsed.String())
+}
+
+func TestIntersectFold(t *testing.T) {
+	assert := assert.New(t)
+
+	intersection := Intersect(Fold{Selector: Equals{Key: "x", Value: "a"}}, Equals{Key: "x", Value: "a"})
+	assert.False(intersection.Matches(Labels{"x": "A"}))
+	assert.True(intersection.Matches(Labels{"x": "a"}))
+
+	intersection = Intersect(Fold{Selector: In{Key: "x", Values: []string{"A"}}}, Fold{Selector: In{Key: "x", Values: []string{"a"}}})
+	assert.True(intersection.Matches(Labels{"x": "a"}))
+}
@@ -0,0 +1,84 @@
+package selector
+
+// KeyBuilder starts a requirement on a key for a `Builder`.
+type KeyBuilder struct {
+	key string
+}
+
+// Key returns a key builder, e.g. `Key("app").Equals("web")`.
+func Key(key string) KeyBuilder {
+	return KeyBuilder{key: key}
+}
+
+// Exists returns a builder for a `HasKey` requirement.
+func (k KeyBuilder) Exists() Builder {
+	return requirement(HasKey(k.key), nil)
+}
+
+// DoesNotExist returns a builder for a `NotHasKey` requirement.
+func (k KeyBuilder) DoesNotExist() Builder {
+	return requirement(NotHasKey(k.key), nil)
+}
+
+// Equals returns a builder for an `Equals` requirement.
+func (k KeyBuilder) Equals(value string) Builder {
+	return requirement(Equals{Key: k.key, Value: value}, nil)
+}
+
+// NotEquals returns a builder for a `NotEquals` requirement.
+func (k KeyBuilder) NotEquals(value string) Builder {
+	return requirement(NotEquals{Key: k.key, Value: value}, nil)
+}
+
+// In returns a builder for an `In` requirement.
+func (k KeyBuilder) In(values ...string) Builder {
+	return requirement(In{Key: k.key, Values: values}, nil)
+}
+
+// NotIn returns a builder for a `NotIn` requirement.
+func (k KeyBuilder) NotIn(values ...string) Builder {
+	return requirement(NotIn{Key: k.key, Values: values}, nil)
+}
+
+// GreaterThan returns a builder for a `GreaterThan` requirement.
+func (k KeyBuilder) GreaterThan(value string) Builder {
+	return requirement(GreaterThan{Key: k.key, Value: value}, nil)
+}
+
+// GreaterThanOrEqual returns a builder for a `GreaterThanOrEqual` requirement.
+func (k KeyBuilder) GreaterThanOrEqual(value string) Builder {
+	return requirement(GreaterThanOrEqual{Key: k.key, Value: value}, nil)
+}
+
+// LessThan returns a builder for a `LessThan` requirement.
+func (k KeyBuilder) LessThan(value string) Builder {
+	return requirement(LessThan{Key: k.key, Value: value}, nil)
+}
+
+// LessThanOrEqual returns a builder for a `LessThanOrEqual` requirement.
+func (k KeyBuilder) LessThanOrEqual(value string) Builder {
+	return requirement(LessThanOrEqual{Key: k.key, Value: value}, nil)
+}
+
+// Like returns a builder for a `Like` requirement.
+func (k KeyBuilder) Like(patterns ...string) Builder {
+	return requirement(Like{Key: k.key, Patterns: patterns}, nil)
+}
+
+// Matches returns a builder for a `Matches` requirement.
+func (k KeyBuilder) Matches(pattern string) Builder {
+	s, err := NewMatches(k.key, pattern)
+	if err != nil {
+		s = Matches{Key: k.key, Pattern: pattern}
+	}
+	return requirement(s, err)
+}
+
+// NotMatches returns a builder for a `NotMatches` requirement.
+func (k KeyBuilder) NotMatches(pattern string) Builder {
+	s, err := NewNotMatches(k.key, pattern)
+	if err != nil {
+		s = NotMatches{Key: k.key, Pattern: pattern}
+	}
+	return requirement(s, err)
+}
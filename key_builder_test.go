@@ -0,0 +1,29 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestKeyBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("x", Key("x").Exists().String())
+	assert.Equal("!x", Key("x").DoesNotExist().String())
+	assert.Equal("x == a", Key("x").Equals("a").String())
+	assert.Equal("x != a", Key("x").NotEquals("a").String())
+	assert.Equal("x in (a, b)", Key("x").In("a", "b").String())
+	assert.Equal("x notin (a)", Key("x").NotIn("a").String())
+	assert.Equal("x > 1", Key("x").GreaterThan("1").String())
+	assert.Equal("x >= 1", Key("x").GreaterThanOrEqual("1").String())
+	assert.Equal("x < 1", Key("x").LessThan("1").String())
+	assert.Equal("x <= 1", Key("x").LessThanOrEqual("1").String())
+	assert.Equal("x like a*", Key("x").Like("a*").String())
+	assert.Equal("x =~ a.*", Key("x").Matches("a.*").String())
+	assert.Equal("x !~ a.*", Key("x").NotMatches("a.*").String())
+
+	selector, err := Key("x").Matches("web-.*").Selector()
+	assert.Nil(err)
+	assert.True(selector.Matches(Labels{"x": "web-1"}))
+}
@@ -0,0 +1,47 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyError is returned by the key transforms for a key that is invalid once renamed.
+type KeyError struct {
+	// Key is the key before it was renamed.
+	Key string
+	// Renamed is the key after it was renamed.
+	Renamed string
+	// Err is the error from `CheckKey` for the renamed key.
+	Err error
+}
+
+// Error implements error.
+func (ke *KeyError) Error() string {
+	return fmt.Sprintf("key %q renamed to %q: %v", ke.Key, ke.Renamed, ke.Err)
+}
+
+// Unwrap returns the underlying validation error.
+func (ke *KeyError) Unwrap() error {
+	return ke.Err
+}
+
+// KeyErrors are the errors for every key that is invalid once renamed, in the order the keys appear.
+type KeyErrors []*KeyError
+
+// Error implements error.
+func (ke KeyErrors) Error() string {
+	messages := make([]string, len(ke))
+	for index, err := range ke {
+		messages[index] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the individual key errors, so `errors.Is` and `errors.As` consider each of them.
+func (ke KeyErrors) Unwrap() []error {
+	errs := make([]error, len(ke))
+	for index, err := range ke {
+		errs[index] = err
+	}
+	return errs
+}
@@ -0,0 +1,63 @@
+package selector
+
+import "fmt"
+
+// LessThan returns if a key's value is numerically less than a value.
+// Both values must be integers or decimals; the selector does not match if the key is absent or not numeric.
+type LessThan struct {
+	Key, Value string
+}
+
+// Matches returns the selector result.
+func (lt LessThan) Matches(labels Labels) bool {
+	if value, hasValue := labels[lt.Key]; hasValue {
+		result, ok := compareNumeric(value, lt.Value)
+		return ok && result < 0
+	}
+	return false
+}
+
+// Validate validates the selector.
+func (lt LessThan) Validate() (err error) {
+	err = CheckKey(lt.Key)
+	if err != nil {
+		return
+	}
+	err = CheckNumber(lt.Value)
+	return
+}
+
+// String returns a string representation of the selector.
+func (lt LessThan) String() string {
+	return fmt.Sprintf("%s < %s", lt.Key, quoteValue(lt.Value))
+}
+
+// LessThanOrEqual returns if a key's value is numerically less than or equal to a value.
+// Both values must be integers or decimals; the selector does not match if the key is absent or not numeric.
+type LessThanOrEqual struct {
+	Key, Value string
+}
+
+// Matches returns the selector result.
+func (lte LessThanOrEqual) Matches(labels Labels) bool {
+	if value, hasValue := labels[lte.Key]; hasValue {
+		result, ok := compareNumeric(value, lte.Value)
+		return ok && result <= 0
+	}
+	return false
+}
+
+// Validate validates the selector.
+func (lte LessThanOrEqual) Validate() (err error) {
+	err = CheckKey(lte.Key)
+	if err != nil {
+		return
+	}
+	err = CheckNumber(lte.Value)
+	return
+}
+
+// String returns a string representation of the selector.
+func (lte LessThanOrEqual) String() string {
+	return fmt.Sprintf("%s <= %s", lte.Key, quoteValue(lte.Value))
+}
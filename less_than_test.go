@@ -0,0 +1,35 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestLessThan(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := LessThan{Key: "cpu-gen", Value: "-1.5"}
+	assert.True(selector.Matches(Labels{"cpu-gen": "-2"}))
+	assert.False(selector.Matches(Labels{"cpu-gen": "-1.5"}))
+	assert.False(selector.Matches(Labels{"cpu-gen": "0"}))
+	assert.False(selector.Matches(Labels{"cpu-gen": ""}))
+	assert.False(selector.Matches(Labels{"foo": "-2"}))
+
+	assert.Nil(selector.Validate())
+	assert.Equal(ErrValueNotNumeric, LessThan{Key: "cpu-gen", Value: ""}.Validate())
+	assert.Equal("cpu-gen < -1.5", selector.String())
+}
+
+func TestLessThanOrEqual(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := LessThanOrEqual{Key: "cpu-gen", Value: "4"}
+	assert.True(selector.Matches(Labels{"cpu-gen": "4"}))
+	assert.True(selector.Matches(Labels{"cpu-gen": "-4"}))
+	assert.False(selector.Matches(Labels{"cpu-gen": "4.01"}))
+	assert.False(selector.Matches(Labels{"foo": "4"}))
+
+	assert.Nil(selector.Validate())
+	assert.Equal("cpu-gen <= 4", selector.String())
+}
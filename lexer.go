@@ -0,0 +1,285 @@
+package selector
+
+// TokenKind is the kind of a token returned by the `Lexer`.
+type TokenKind int
+
+const (
+	// KindInvalid is text that cannot appear where it was found, e.g. an unknown operator.
+	KindInvalid TokenKind = iota
+	// KindKey is a label key.
+	KindKey
+	// KindOperator is a requirement operator, e.g. `==` or `notin`.
+	KindOperator
+	// KindValue is a value, including any quotes or escapes in the extended dialect.
+	KindValue
+	// KindBang is the `!` of a `!key` requirement or a negated group.
+	KindBang
+	// KindComma is a comma between requirements or between values in a set.
+	KindComma
+	// KindOpenParens is the start of a value set or a group.
+	KindOpenParens
+	// KindCloseParens is the end of a value set or a group.
+	KindCloseParens
+	// KindOr is a disjunction operator in the extended dialect, e.g. `||` or `or`.
+	KindOr
+	// KindEnd is returned once the input is exhausted.
+	KindEnd
+)
+
+// String returns the name of the token kind.
+func (k TokenKind) String() string {
+	switch k {
+	case KindInvalid:
+		return "invalid"
+	case KindKey:
+		return "key"
+	case KindOperator:
+		return "operator"
+	case KindValue:
+		return "value"
+	case KindBang:
+		return "bang"
+	case KindComma:
+		return "comma"
+	case KindOpenParens:
+		return "open parens"
+	case KindCloseParens:
+		return "close parens"
+	case KindOr:
+		return "or"
+	case KindEnd:
+		return "end"
+	}
+	return "unknown"
+}
+
+// Token is a lexed token; `Start` and `End` are byte offsets into the input, and `Text` is `input[Start:End]`.
+type Token struct {
+	Kind       TokenKind
+	Text       string
+	Start, End int
+}
+
+// lexer states, i.e. what the lexer expects next.
+const (
+	lexKey = iota
+	lexOperator
+	lexValue
+	lexPatterns
+	lexSet
+	lexSetValue
+	lexSetComma
+	lexNext
+)
+
+// Lexer splits a selector into tokens using the same readers as the parser.
+// It never fails; text that cannot be lexed where it is found is returned as a `KindInvalid` token
+// and lexing continues, so partial input such as `app in (web,` produces every token it can.
+// Keys and values are not validated; use `Parse` for that.
+type Lexer struct {
+	p     *Parser
+	state int
+	depth int
+}
+
+// NewLexer returns a lexer for a query with the given options.
+func NewLexer(query string, opts Options) *Lexer {
+	return &Lexer{p: NewParser(query, opts)}
+}
+
+// Lex returns all the tokens in a query, excluding the final `KindEnd` token.
+func Lex(query string, opts Options) []Token {
+	return NewLexer(query, opts).Tokens()
+}
+
+// Tokens returns the remaining tokens, excluding the final `KindEnd` token.
+func (l *Lexer) Tokens() (tokens []Token) {
+	for {
+		token := l.Next()
+		if token.Kind == KindEnd {
+			return
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+// Expected returns descriptions of the tokens that are valid next, in the style of `ParseError.Expected`,
+// e.g. for autocomplete. It is empty once the lexer has seen invalid text it cannot place.
+func (l *Lexer) Expected() []string {
+	p := l.p
+	switch l.state {
+	case lexKey:
+		if p.opts.Extended() {
+			return []string{TokenKey, string(Bang), string(OpenParens)}
+		}
+		return []string{TokenKey, string(Bang)}
+	case lexOperator:
+		return append(p.operators(), l.next()...)
+	case lexValue:
+		return []string{TokenValue}
+	case lexPatterns:
+		return []string{TokenValue, string(OpenParens)}
+	case lexSet:
+		return []string{string(OpenParens)}
+	case lexSetValue:
+		return []string{TokenValue, string(CloseParens)}
+	case lexSetComma:
+		return []string{string(Comma), string(CloseParens)}
+	}
+	return l.next()
+}
+
+// next returns the tokens that may follow a complete requirement.
+func (l *Lexer) next() []string {
+	expected := []string{string(Comma)}
+	if l.p.opts.Extended() {
+		expected = append(expected, OpOr, OpOrKeyword)
+	}
+	if l.depth > 0 {
+		expected = append(expected, string(CloseParens))
+	}
+	return append(expected, TokenEnd)
+}
+
+// Next returns the next token, or a `KindEnd` token at the end of the input.
+func (l *Lexer) Next() Token {
+	p := l.p
+	p.skipWhiteSpace()
+	start := p.pos
+	if p.done() {
+		return Token{Kind: KindEnd, Start: start, End: start}
+	}
+
+	ch := p.current()
+	switch l.state {
+	case lexKey:
+		if ch == Bang {
+			p.advance()
+			return l.token(KindBang, start)
+		}
+		if ch == OpenParens && p.opts.Extended() {
+			p.advance()
+			l.depth++
+			return l.token(KindOpenParens, start)
+		}
+		if key := p.readWord(); len(key) > 0 {
+			l.state = lexOperator
+			return l.token(KindKey, start)
+		}
+	case lexOperator:
+		if token, ok := l.readNext(start); ok {
+			return token
+		}
+		op, err := p.readOp()
+		if err != nil {
+			return l.invalid(start)
+		}
+		switch op {
+		case OpIn, OpNotIn:
+			l.state = lexSet
+		case OpLike, OpLikeSymbol:
+			l.state = lexPatterns
+		default:
+			l.state = lexValue
+		}
+		return l.token(KindOperator, start)
+	case lexPatterns:
+		if ch == OpenParens {
+			p.advance()
+			l.state = lexSetValue
+			return l.token(KindOpenParens, start)
+		}
+		l.state = lexValue
+		return l.Next()
+	case lexValue:
+		if token, ok := l.readValue(start); ok {
+			l.state = lexNext
+			return token
+		}
+		// an empty value, e.g. `x=,y`
+		l.state = lexNext
+		return l.Next()
+	case lexSet:
+		if ch == OpenParens {
+			p.advance()
+			l.state = lexSetValue
+			return l.token(KindOpenParens, start)
+		}
+	case lexSetValue, lexSetComma:
+		if ch == CloseParens {
+			p.advance()
+			l.state = lexNext
+			return l.token(KindCloseParens, start)
+		}
+		if ch == Comma {
+			p.advance()
+			l.state = lexSetValue
+			return l.token(KindComma, start)
+		}
+		if l.state == lexSetValue {
+			if token, ok := l.readValue(start); ok {
+				l.state = lexSetComma
+				return token
+			}
+		}
+	case lexNext:
+		if token, ok := l.readNext(start); ok {
+			return token
+		}
+	}
+	return l.invalid(start)
+}
+
+// readNext reads a token that may follow a complete requirement.
+func (l *Lexer) readNext(start int) (Token, bool) {
+	p := l.p
+	switch ch := p.current(); {
+	case ch == Comma:
+		p.advance()
+		l.state = lexKey
+		return l.token(KindComma, start), true
+	case ch == CloseParens && l.depth > 0:
+		p.advance()
+		l.depth--
+		l.state = lexNext
+		return l.token(KindCloseParens, start), true
+	case p.opts.Extended() && p.readOr():
+		l.state = lexKey
+		return l.token(KindOr, start), true
+	}
+	return Token{}, false
+}
+
+// readValue reads a value, returning false if there is no value at the cursor.
+func (l *Lexer) readValue(start int) (Token, bool) {
+	p := l.p
+	if p.opts.Extended() {
+		if _, err := p.readExtendedWord(); err != nil {
+			// an unterminated quote or escape runs to the end of the input.
+			p.pos = len(p.s)
+			return l.token(KindInvalid, start), true
+		}
+	} else {
+		p.readWord()
+	}
+	if p.pos == start {
+		return Token{}, false
+	}
+	return l.token(KindValue, start), true
+}
+
+// invalid returns an invalid token for the word at the cursor, or a single rune if there isn't a word.
+func (l *Lexer) invalid(start int) Token {
+	p := l.p
+	p.pos = start
+	p.readWord()
+	if p.pos == start {
+		p.advance()
+	}
+	return l.token(KindInvalid, start)
+}
+
+// token returns a token from the start offset to the cursor.
+func (l *Lexer) token(kind TokenKind, start int) Token {
+	return Token{Kind: kind, Text: l.p.s[start:l.p.pos], Start: start, End: l.p.pos}
+}
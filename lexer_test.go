@@ -0,0 +1,117 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func kinds(tokens []Token) (kinds []TokenKind) {
+	for _, token := range tokens {
+		kinds = append(kinds, token.Kind)
+	}
+	return
+}
+
+func texts(tokens []Token) (texts []string) {
+	for _, token := range tokens {
+		texts = append(texts, token.Text)
+	}
+	return
+}
+
+func TestLex(t *testing.T) {
+	assert := assert.New(t)
+
+	tokens := Lex("x == a, !y, z in (b,, c), w", Options{})
+	assert.Equal([]string{"x", "==", "a", ",", "!", "y", ",", "z", "in", "(", "b", ",", ",", "c", ")", ",", "w"}, texts(tokens))
+	assert.Equal([]TokenKind{
+		KindKey, KindOperator, KindValue, KindComma,
+		KindBang, KindKey, KindComma,
+		KindKey, KindOperator, KindOpenParens, KindValue, KindComma, KindComma, KindValue, KindCloseParens, KindComma,
+		KindKey,
+	}, kinds(tokens))
+	assert.Equal(Token{Kind: KindOperator, Text: "==", Start: 2, End: 4}, tokens[1])
+}
+
+func TestLexSpans(t *testing.T) {
+	assert := assert.New(t)
+
+	query := "  app  notin ( web ,api )  "
+	for _, token := range Lex(query, Options{}) {
+		assert.Equal(query[token.Start:token.End], token.Text)
+	}
+
+	lexer := NewLexer(query, Options{})
+	lexer.Tokens()
+	end := lexer.Next()
+	assert.Equal(KindEnd, end.Kind)
+	assert.Equal(len(query), end.Start)
+}
+
+func TestLexEmptyValue(t *testing.T) {
+	assert := assert.New(t)
+
+	tokens := Lex("x=,y", Options{})
+	assert.Equal([]TokenKind{KindKey, KindOperator, KindComma, KindKey}, kinds(tokens))
+}
+
+func TestLexExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	tokens := Lex(`!(x == "a, b" || y like (web-*, api)), z >= 4`, Options{Dialect: DialectExtended})
+	assert.Equal([]string{"!", "(", "x", "==", `"a, b"`, "||", "y", "like", "(", "web-*", ",", "api", ")", ")", ",", "z", ">=", "4"}, texts(tokens))
+	assert.Equal([]TokenKind{
+		KindBang, KindOpenParens, KindKey, KindOperator, KindValue, KindOr,
+		KindKey, KindOperator, KindOpenParens, KindValue, KindComma, KindValue, KindCloseParens, KindCloseParens, KindComma,
+		KindKey, KindOperator, KindValue,
+	}, kinds(tokens))
+
+	tokens = Lex(`x or y ~= web-*`, Options{Dialect: DialectExtended})
+	assert.Equal([]TokenKind{KindKey, KindOr, KindKey, KindOperator, KindValue}, kinds(tokens))
+}
+
+func TestLexPartial(t *testing.T) {
+	assert := assert.New(t)
+
+	lexer := NewLexer("app in (web,", Options{})
+	tokens := lexer.Tokens()
+	assert.Equal([]TokenKind{KindKey, KindOperator, KindOpenParens, KindValue, KindComma}, kinds(tokens))
+	assert.Equal([]string{TokenValue, string(CloseParens)}, lexer.Expected())
+
+	lexer = NewLexer("app ", Options{})
+	lexer.Tokens()
+	assert.Equal([]string{OpEquals, OpDoubleEquals, OpNotEquals, OpIn, OpNotIn, string(Comma), TokenEnd}, lexer.Expected())
+
+	lexer = NewLexer("", Options{Dialect: DialectExtended})
+	assert.Empty(lexer.Tokens())
+	assert.Equal([]string{TokenKey, string(Bang), string(OpenParens)}, lexer.Expected())
+
+	tokens = Lex(`x == "web`, Options{Dialect: DialectExtended})
+	assert.Equal([]TokenKind{KindKey, KindOperator, KindInvalid}, kinds(tokens))
+	assert.Equal(`"web`, tokens[2].Text)
+}
+
+func TestLexInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	tokens := Lex("x foo bar, y", Options{})
+	assert.Equal([]TokenKind{KindKey, KindInvalid, KindInvalid, KindComma, KindKey}, kinds(tokens))
+	assert.Equal("foo", tokens[1].Text)
+
+	tokens = Lex(",x)", Options{})
+	assert.Equal([]TokenKind{KindInvalid, KindKey, KindInvalid}, kinds(tokens))
+	assert.Equal(",", tokens[0].Text)
+	assert.Equal(")", tokens[2].Text)
+
+	tokens = Lex("x in web", Options{})
+	assert.Equal([]TokenKind{KindKey, KindOperator, KindInvalid}, kinds(tokens))
+}
+
+func TestTokenKindString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("operator", KindOperator.String())
+	assert.Equal("close parens", KindCloseParens.String())
+	assert.Equal("unknown", TokenKind(-1).String())
+}
@@ -0,0 +1,46 @@
+package selector
+
+import "fmt"
+
+// Like returns if a key's value matches any of a set of glob patterns.
+// Patterns support `*`, `?`, character classes (`[a-z]`, `[!a-z]`) and backslash escapes,
+// and must match the entire value; the selector does not match if the key is absent.
+type Like struct {
+	Key      string
+	Patterns []string
+}
+
+// Matches returns the selector result.
+func (l Like) Matches(labels Labels) bool {
+	if value, hasValue := labels[l.Key]; hasValue {
+		for _, pattern := range l.Patterns {
+			if globMatch(pattern, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate validates the selector.
+func (l Like) Validate() (err error) {
+	err = CheckKey(l.Key)
+	if err != nil {
+		return
+	}
+	for _, pattern := range l.Patterns {
+		err = CheckGlob(pattern)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// String returns a string representation of the selector.
+func (l Like) String() string {
+	if len(l.Patterns) == 1 {
+		return fmt.Sprintf("%s like %s", l.Key, quoteSetPattern(l.Patterns[0]))
+	}
+	return fmt.Sprintf("%s like (%s)", l.Key, quoteSetPatterns(l.Patterns))
+}
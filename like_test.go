@@ -0,0 +1,59 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestLike(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := Like{Key: "app", Patterns: []string{"web-*", "api-?"}}
+	assert.True(selector.Matches(Labels{"app": "web-canary"}))
+	assert.True(selector.Matches(Labels{"app": "api-1"}))
+	assert.False(selector.Matches(Labels{"app": "api-12"}))
+	assert.False(selector.Matches(Labels{"foo": "web-1"}))
+
+	assert.Nil(selector.Validate())
+	assert.Equal(ErrInvalidGlob, Like{Key: "app", Patterns: []string{"web-["}}.Validate())
+
+	assert.Equal("app like (web-*, api-?)", selector.String())
+	assert.Equal("app like web-*", Like{Key: "app", Patterns: []string{"web-*"}}.String())
+	assert.Equal(`app like "(web)*"`, Like{Key: "app", Patterns: []string{"(web)*"}}.String())
+}
+
+func TestLikeEscapes(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	for _, query := range []string{`x like web-\*`, `x ~= "web-\*"`, `x like (web-\*, "api\?")`} {
+		selector, err := ParseWithOptions(query, extended)
+		assert.Nil(err, query)
+		assert.True(selector.Matches(Labels{"x": "web-*"}), query)
+		assert.False(selector.Matches(Labels{"x": "web-1"}), query)
+	}
+
+	selector, err := ParseWithOptions(`x like (web-\*, "api\?")`, extended)
+	assert.Nil(err)
+	assert.Equal([]string{`web-\*`, `api\?`}, selector.(Like).Patterns)
+	assert.True(selector.Matches(Labels{"x": "api?"}))
+	assert.False(selector.Matches(Labels{"x": "api1"}))
+}
+
+func TestLikeStringRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	selectors := []Like{
+		{Key: "x", Patterns: []string{`web-\*`}},
+		{Key: "x", Patterns: []string{`web-\*`, `a\\`, "", "b c*", `it's*`}},
+		{Key: "x", Patterns: []string{"(web)*"}},
+	}
+	for _, s := range selectors {
+		parsed, err := ParseWithOptions(s.String(), Options{Dialect: DialectExtended})
+		assert.Nil(err, s.String())
+		assert.Equal(s, parsed, s.String())
+	}
+	assert.Equal(`x like web-\*`, selectors[0].String())
+}
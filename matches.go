@@ -0,0 +1,80 @@
+package selector
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+)
+
+// Matches returns if a key's value matches a regular expression.
+// The pattern must match the entire value, and the selector does not match if the key is absent.
+type Matches struct {
+	Key, Pattern string
+
+	// compiled is the anchored pattern, compiled once by `NewMatches` or the parser.
+	compiled *regexp.Regexp
+}
+
+// NewMatches returns a `Matches` selector with the pattern compiled.
+func NewMatches(key, pattern string) (Matches, error) {
+	compiled, err := compilePattern(pattern)
+	if err != nil {
+		return Matches{}, err
+	}
+	return Matches{Key: key, Pattern: pattern, compiled: compiled}, nil
+}
+
+// Matches returns the selector result.
+func (m Matches) Matches(labels Labels) bool {
+	if value, hasValue := labels[m.Key]; hasValue {
+		compiled, err := m.regexp()
+		return err == nil && compiled.MatchString(value)
+	}
+	return false
+}
+
+// Validate validates the selector.
+func (m Matches) Validate() (err error) {
+	err = CheckKey(m.Key)
+	if err != nil {
+		return
+	}
+	_, err = m.regexp()
+	return
+}
+
+// String returns a string representation of the selector.
+func (m Matches) String() string {
+	return fmt.Sprintf("%s =~ %s", m.Key, quotePattern(m.Pattern))
+}
+
+// regexp returns the compiled pattern, compiling it if the selector was not
+// created by `NewMatches` or the parser.
+func (m Matches) regexp() (*regexp.Regexp, error) {
+	if m.compiled != nil {
+		return m.compiled, nil
+	}
+	return compilePattern(m.Pattern)
+}
+
+// caseInsensitiveFlag is the regular expression flag for case insensitive matching.
+const caseInsensitiveFlag = "(?i)"
+
+// compilePattern compiles a pattern anchored to match an entire value.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	return compilePatternFlags(pattern, "")
+}
+
+// compilePatternFlags compiles a pattern anchored to match an entire value with the given flags prefixed.
+// The pattern is parsed on its own first, so an unbalanced group can't close the anchoring group
+// and escape the anchors, e.g. `web)|(?:zzz`.
+func compilePatternFlags(pattern, flags string) (*regexp.Regexp, error) {
+	if _, err := syntax.Parse(pattern, syntax.Perl); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+	compiled, err := regexp.Compile(flags + "^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+	return compiled, nil
+}
@@ -0,0 +1,82 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := NewMatches("app", "web-[0-9]+|api")
+	assert.Nil(err)
+	assert.True(selector.Matches(Labels{"app": "web-12"}))
+	assert.True(selector.Matches(Labels{"app": "api"}))
+	assert.False(selector.Matches(Labels{"app": "web-12a"}), "patterns are anchored")
+	assert.False(selector.Matches(Labels{"app": "an-api"}), "patterns are anchored")
+	assert.False(selector.Matches(Labels{"foo": "api"}))
+
+	assert.Nil(selector.Validate())
+	assert.Equal(`app =~ "web-[0-9]+|api"`, selector.String())
+
+	uncompiled := Matches{Key: "app", Pattern: "web-.*"}
+	assert.True(uncompiled.Matches(Labels{"app": "web-1"}))
+	assert.Nil(uncompiled.Validate())
+
+	_, err = NewMatches("app", "web-(")
+	assert.True(errors.Is(err, ErrInvalidPattern))
+	assert.True(errors.Is(Matches{Key: "app", Pattern: "web-("}.Validate(), ErrInvalidPattern))
+	assert.False(Matches{Key: "app", Pattern: "web-("}.Matches(Labels{"app": "web-("}))
+}
+
+func TestMatchesUnbalancedPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewMatches("app", "web)|(?:zzz")
+	assert.True(errors.Is(err, ErrInvalidPattern))
+	_, err = NewNotMatches("app", "web)|(?:zzz")
+	assert.True(errors.Is(err, ErrInvalidPattern))
+	assert.False(Matches{Key: "app", Pattern: "web)|(?:zzz"}.Matches(Labels{"app": "web-1"}))
+
+	_, err = ParseWithOptions(`app =~ "web)|(?:zzz"`, Options{Dialect: DialectExtended})
+	assert.True(errors.Is(err, ErrInvalidPattern))
+}
+
+func TestMatchesEscapes(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	selector, err := ParseWithOptions(`x =~ a\.b`, extended)
+	assert.Nil(err)
+	assert.Equal(`a\.b`, selector.(Matches).Pattern)
+	assert.True(selector.Matches(Labels{"x": "a.b"}))
+	assert.False(selector.Matches(Labels{"x": "axb"}))
+
+	selector, err = ParseWithOptions(`x =~ "web-\d+"`, extended)
+	assert.Nil(err)
+	assert.Equal(`web-\d+`, selector.(Matches).Pattern)
+	assert.True(selector.Matches(Labels{"x": "web-12"}))
+	assert.False(selector.Matches(Labels{"x": "web-dd"}))
+
+	selector, err = ParseWithOptions(`x !~ 'it\'s\s.*', y`, extended)
+	assert.Nil(err)
+	assert.Equal(`it's\s.*`, selector.(And)[0].(NotMatches).Pattern)
+}
+
+func TestMatchesStringRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, pattern := range []string{`a\.b`, `web-\d+`, `(a|b)\\`, `a\"b`, `a\'b`, `"quoted"`, `a{1,2} b`, `\(x\)`, ""} {
+		selector := And{Matches{Key: "x", Pattern: pattern}, NotMatches{Key: "y", Pattern: pattern}}
+		parsed, err := ParseWithOptions(selector.String(), Options{Dialect: DialectExtended})
+		assert.Nil(err, selector.String())
+		assert.Equal(pattern, parsed.(And)[0].(Matches).Pattern, selector.String())
+		assert.Equal(pattern, parsed.(And)[1].(NotMatches).Pattern, selector.String())
+	}
+	assert.Equal(`x =~ a\.b`, Matches{Key: "x", Pattern: `a\.b`}.String())
+	assert.Equal(`x =~ "a\"b"`, Matches{Key: "x", Pattern: `a"b`}.String())
+	assert.Equal(`x =~ 'a\"b'`, Matches{Key: "x", Pattern: `a\"b`}.String())
+}
@@ -0,0 +1,93 @@
+package selector
+
+import "fmt"
+
+// Not is a combination selector that inverts the result of another selector.
+type Not struct {
+	Selector Selector
+}
+
+// Matches returns if the selector does not match the labels.
+func (n Not) Matches(labels Labels) bool {
+	return !n.Selector.Matches(labels)
+}
+
+// Validate validates the inverted selector.
+func (n Not) Validate() error {
+	if n.Selector == nil {
+		return ErrInvalidSelector
+	}
+	return n.Selector.Validate()
+}
+
+// String returns a string representation for the selector.
+// Where the inverse has a simpler form it is used instead, e.g. `Not{Equals{...}}` prints as `!=`.
+func (n Not) String() string {
+	if n.Selector == nil {
+		return ""
+	}
+	if negated, isSimplified := negate(n.Selector); isSimplified {
+		if _, isNot := negated.(Not); !isNot {
+			return negated.String()
+		}
+	}
+	return fmt.Sprintf("!(%s)", n.Selector.String())
+}
+
+// Negate returns a selector that matches exactly when the given selector does not.
+// The leaf selector types are inverted to their opposites, e.g. `Equals` becomes `NotEquals`,
+// double negations are removed, and anything else is wrapped in a `Not`.
+func Negate(s Selector) Selector {
+	negated, _ := negate(s)
+	return negated
+}
+
+// negate returns the inverse of a selector, and if it could be simplified beyond wrapping it in a `Not`.
+func negate(s Selector) (Selector, bool) {
+	switch typed := s.(type) {
+	case Not:
+		return typed.Selector, true
+	case Equals:
+		return NotEquals{Key: typed.Key, Value: typed.Value, rules: typed.rules}, true
+	case NotEquals:
+		return Equals{Key: typed.Key, Value: typed.Value, rules: typed.rules}, true
+	case HasKey:
+		return NotHasKey(typed), true
+	case NotHasKey:
+		return HasKey(typed), true
+	case Matches:
+		return NotMatches(typed), true
+	case NotMatches:
+		return Matches(typed), true
+	case In:
+		if typed.RequireKey {
+			return NotIn{Key: typed.Key, Values: typed.Values, rules: typed.rules}, true
+		}
+		// `in` matches when the key is absent, so the inverse requires the key.
+		return And{HasKey(typed.Key), NotIn{Key: typed.Key, Values: typed.Values, rules: typed.rules}}, true
+	case NotIn:
+		// `notin` matches when the key is absent, so the inverse requires the key.
+		return And{HasKey(typed.Key), In{Key: typed.Key, Values: typed.Values, rules: typed.rules}}, true
+	}
+	return Not{Selector: s}, false
+}
+
+// Children returns the inverted selector.
+func (n Not) Children() []Selector {
+	if n.Selector == nil {
+		return nil
+	}
+	return []Selector{n.Selector}
+}
+
+// WithChildren returns the inverse of the given selector; several selectors are combined in an `And`,
+// and nil is returned if there are none.
+func (n Not) WithChildren(children []Selector) Selector {
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		return Not{Selector: children[0]}
+	}
+	return Not{Selector: And(children)}
+}
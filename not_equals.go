@@ -5,6 +5,9 @@ import "fmt"
 // NotEquals returns if a key strictly equals a value.
 type NotEquals struct {
 	Key, Value string
+
+	// rules are the value rules of the dialect that parsed the selector, which `Validate` checks.
+	rules valueRules
 }
 
 // Matches returns the selector result.
@@ -21,11 +24,11 @@ func (ne NotEquals) Validate() (err error) {
 	if err != nil {
 		return
 	}
-	err = CheckValue(ne.Value)
+	err = ne.rules.check(ne.Value)
 	return
 }
 
 // String returns a string representation of the selector.
 func (ne NotEquals) String() string {
-	return fmt.Sprintf("%s != %s", ne.Key, ne.Value)
+	return fmt.Sprintf("%s != %s", ne.Key, quoteValue(ne.Value))
 }
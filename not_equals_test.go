@@ -17,4 +17,5 @@ func TestNotEquals(t *testing.T) {
 	assert.True(NotEquals{Key: "zoo", Value: "buzz"}.Matches(valid))
 	assert.True(NotEquals{Key: "foo", Value: "bar"}.Matches(valid))
 	assert.Equal("foo != bar", NotEquals{Key: "foo", Value: "bar"}.String())
+	assert.Equal(`foo != "a \\ \"b\""`, NotEquals{Key: "foo", Value: `a \ "b"`}.String())
 }
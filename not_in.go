@@ -1,14 +1,14 @@
 package selector
 
-import (
-	"fmt"
-	"strings"
-)
+import "fmt"
 
 // NotIn returns if a key does not match a set of values.
 type NotIn struct {
 	Key    string
 	Values []string
+
+	// rules are the value rules of the dialect that parsed the selector, which `Validate` checks.
+	rules valueRules
 }
 
 // Matches returns the selector result.
@@ -32,7 +32,7 @@ func (ni NotIn) Validate() (err error) {
 		return
 	}
 	for _, v := range ni.Values {
-		err = CheckValue(v)
+		err = ni.rules.check(v)
 		if err != nil {
 			return
 		}
@@ -42,5 +42,5 @@ func (ni NotIn) Validate() (err error) {
 
 // String returns a string representation of the selector.
 func (ni NotIn) String() string {
-	return fmt.Sprintf("%s notin (%s)", ni.Key, strings.Join(ni.Values, ", "))
+	return fmt.Sprintf("%s notin (%s)", ni.Key, quoteSetValues(ni.Values))
 }
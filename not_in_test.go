@@ -27,4 +27,5 @@ func TestNotIn(t *testing.T) {
 	assert.True(selector.Matches(missing))
 	assert.False(selector.Matches(invalid))
 	assert.Equal("foo notin (bar, far)", selector.String())
+	assert.Equal(`foo notin ("'bar'", far)`, NotIn{Key: "foo", Values: []string{"'bar'", "far"}}.String())
 }
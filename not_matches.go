@@ -0,0 +1,57 @@
+package selector
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// NotMatches returns if a key's value does not match a regular expression.
+// The pattern must match the entire value, and the selector matches if the key is absent.
+type NotMatches struct {
+	Key, Pattern string
+
+	// compiled is the anchored pattern, compiled once by `NewNotMatches` or the parser.
+	compiled *regexp.Regexp
+}
+
+// NewNotMatches returns a `NotMatches` selector with the pattern compiled.
+func NewNotMatches(key, pattern string) (NotMatches, error) {
+	compiled, err := compilePattern(pattern)
+	if err != nil {
+		return NotMatches{}, err
+	}
+	return NotMatches{Key: key, Pattern: pattern, compiled: compiled}, nil
+}
+
+// Matches returns the selector result.
+func (nm NotMatches) Matches(labels Labels) bool {
+	if value, hasValue := labels[nm.Key]; hasValue {
+		compiled, err := nm.regexp()
+		return err == nil && !compiled.MatchString(value)
+	}
+	return true
+}
+
+// Validate validates the selector.
+func (nm NotMatches) Validate() (err error) {
+	err = CheckKey(nm.Key)
+	if err != nil {
+		return
+	}
+	_, err = nm.regexp()
+	return
+}
+
+// String returns a string representation of the selector.
+func (nm NotMatches) String() string {
+	return fmt.Sprintf("%s !~ %s", nm.Key, quotePattern(nm.Pattern))
+}
+
+// regexp returns the compiled pattern, compiling it if the selector was not
+// created by `NewNotMatches` or the parser.
+func (nm NotMatches) regexp() (*regexp.Regexp, error) {
+	if nm.compiled != nil {
+		return nm.compiled, nil
+	}
+	return compilePattern(nm.Pattern)
+}
@@ -0,0 +1,25 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestNotMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := NewNotMatches("app", "web-.*")
+	assert.Nil(err)
+	assert.True(selector.Matches(Labels{"app": "api"}))
+	assert.True(selector.Matches(Labels{"app": "my-web-1"}), "patterns are anchored")
+	assert.True(selector.Matches(Labels{"foo": "web-1"}))
+	assert.False(selector.Matches(Labels{"app": "web-1"}))
+
+	assert.Nil(selector.Validate())
+	assert.Equal(`app !~ web-.*`, selector.String())
+
+	_, err = NewNotMatches("app", "[")
+	assert.True(errors.Is(err, ErrInvalidPattern))
+}
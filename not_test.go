@@ -0,0 +1,73 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestNot(t *testing.T) {
+	assert := assert.New(t)
+
+	valid := Labels{
+		"foo": "far",
+		"moo": "lar",
+	}
+	invalid := Labels{
+		"foo": "bar",
+		"moo": "lar",
+	}
+
+	selector := Not{Selector: And{Equals{Key: "foo", Value: "bar"}, HasKey("moo")}}
+	assert.True(selector.Matches(valid))
+	assert.False(selector.Matches(invalid))
+	assert.Nil(selector.Validate())
+	assert.NotNil(Not{}.Validate())
+	assert.Equal("!(foo == bar, moo)", selector.String())
+}
+
+func TestNotString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("foo != bar", Not{Selector: Equals{Key: "foo", Value: "bar"}}.String())
+	assert.Equal("foo == bar", Not{Selector: NotEquals{Key: "foo", Value: "bar"}}.String())
+	assert.Equal("!foo", Not{Selector: HasKey("foo")}.String())
+	assert.Equal("foo", Not{Selector: NotHasKey("foo")}.String())
+	assert.Equal("foo, foo notin (bar)", Not{Selector: In{Key: "foo", Values: []string{"bar"}}}.String())
+	assert.Equal("foo, foo in (bar)", Not{Selector: NotIn{Key: "foo", Values: []string{"bar"}}}.String())
+	assert.Equal(`foo !~ b.*`, Not{Selector: Matches{Key: "foo", Pattern: "b.*"}}.String())
+	assert.Equal("!(foo || bar)", Not{Selector: Or{HasKey("foo"), HasKey("bar")}}.String())
+	assert.Equal("foo || bar", Not{Selector: Not{Selector: Or{HasKey("foo"), HasKey("bar")}}}.String())
+}
+
+func TestNegate(t *testing.T) {
+	assert := assert.New(t)
+
+	labelSets := []Labels{
+		{},
+		{"foo": "bar"},
+		{"foo": "baz"},
+		{"foo": ""},
+		{"moo": "bar"},
+	}
+	selectors := []Selector{
+		Equals{Key: "foo", Value: "bar"},
+		NotEquals{Key: "foo", Value: "bar"},
+		HasKey("foo"),
+		NotHasKey("foo"),
+		In{Key: "foo", Values: []string{"bar", ""}},
+		NotIn{Key: "foo", Values: []string{"bar", ""}},
+		And{HasKey("foo"), HasKey("moo")},
+		Not{Selector: HasKey("foo")},
+	}
+
+	for _, selector := range selectors {
+		negated := Negate(selector)
+		for _, labels := range labelSets {
+			assert.Equal(!selector.Matches(labels), negated.Matches(labels), selector.String())
+		}
+	}
+
+	assert.Equal(Not{Selector: And{HasKey("foo"), HasKey("moo")}}, Negate(And{HasKey("foo"), HasKey("moo")}))
+	assert.Equal(HasKey("foo"), Negate(Not{Selector: HasKey("foo")}))
+}
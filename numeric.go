@@ -0,0 +1,117 @@
+package selector
+
+import "strings"
+
+// isNumeric returns if a value is an integer or decimal, e.g. `-12` or `4.5`.
+func isNumeric(value string) bool {
+	_, _, _, ok := splitNumeric(value)
+	return ok
+}
+
+// compareNumeric compares two integer or decimal values without converting them to floats,
+// returning -1, 0 or 1 and if both values were numeric.
+func compareNumeric(a, b string) (int, bool) {
+	aNegative, aWhole, aFraction, ok := splitNumeric(a)
+	if !ok {
+		return 0, false
+	}
+	bNegative, bWhole, bFraction, ok := splitNumeric(b)
+	if !ok {
+		return 0, false
+	}
+
+	if aNegative != bNegative {
+		if aNegative {
+			return -1, true
+		}
+		return 1, true
+	}
+
+	result := compareMagnitude(aWhole, aFraction, bWhole, bFraction)
+	if aNegative {
+		return -result, true
+	}
+	return result, true
+}
+
+// compareMagnitude compares two normalized unsigned decimals.
+func compareMagnitude(aWhole, aFraction, bWhole, bFraction string) int {
+	if len(aWhole) != len(bWhole) {
+		if len(aWhole) < len(bWhole) {
+			return -1
+		}
+		return 1
+	}
+	if result := strings.Compare(aWhole, bWhole); result != 0 {
+		return result
+	}
+	return strings.Compare(aFraction, bFraction)
+}
+
+// splitNumeric splits a decimal into its sign, whole digits without leading zeros,
+// and fractional digits without trailing zeros.
+// Valid values take the form `[+-]?[0-9]+(\.[0-9]+)?`.
+func splitNumeric(value string) (negative bool, whole, fraction string, ok bool) {
+	if len(value) == 0 {
+		return
+	}
+	switch value[0] {
+	case '-':
+		negative = true
+		value = value[1:]
+	case '+':
+		value = value[1:]
+	}
+
+	var state int
+	var pos int
+	var dot = -1
+	for pos = 0; pos < len(value); pos++ {
+		ch := value[pos]
+		switch state {
+		case 0: // at least one whole digit
+			if !isDigit(ch) {
+				return
+			}
+			state = 1
+		case 1: // whole digits or the decimal point
+			if ch == byte(Dot) {
+				dot = pos
+				state = 2
+				continue
+			}
+			if !isDigit(ch) {
+				return
+			}
+		case 2: // at least one fractional digit
+			if !isDigit(ch) {
+				return
+			}
+			state = 3
+		case 3: // fractional digits
+			if !isDigit(ch) {
+				return
+			}
+		}
+	}
+	if state != 1 && state != 3 {
+		return
+	}
+
+	if dot < 0 {
+		whole = value
+	} else {
+		whole, fraction = value[:dot], value[dot+1:]
+	}
+	whole = strings.TrimLeft(whole, "0")
+	fraction = strings.TrimRight(fraction, "0")
+	if len(whole) == 0 && len(fraction) == 0 {
+		negative = false // -0 == 0
+	}
+	ok = true
+	return
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
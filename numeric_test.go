@@ -0,0 +1,54 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestIsNumeric(t *testing.T) {
+	assert := assert.New(t)
+
+	good := []string{"0", "4", "-4", "+4", "007", "4.5", "-0.25", "12345678901234567890"}
+	for _, value := range good {
+		assert.True(isNumeric(value), value)
+	}
+	bad := []string{"", "-", "+", "4.", ".5", "4.5.6", "1e6", "four", "4a", " 4", "--4"}
+	for _, value := range bad {
+		assert.False(isNumeric(value), value)
+	}
+}
+
+func TestCompareNumeric(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		A, B     string
+		Expected int
+	}{
+		{"1", "2", -1},
+		{"2", "1", 1},
+		{"2", "2", 0},
+		{"10", "9", 1},
+		{"007", "7", 0},
+		{"7.50", "7.5", 0},
+		{"-0", "0", 0},
+		{"-0.0", "+0", 0},
+		{"-1", "1", -1},
+		{"-10", "-9", -1},
+		{"-1.5", "-1.25", -1},
+		{"0.5", "0.45", 1},
+		{"1.05", "1.5", -1},
+		{"12345678901234567891", "12345678901234567890", 1},
+	}
+	for _, testCase := range testCases {
+		result, ok := compareNumeric(testCase.A, testCase.B)
+		assert.True(ok)
+		assert.Equal(testCase.Expected, result, testCase.A, " ", testCase.B)
+	}
+
+	_, ok := compareNumeric("a", "1")
+	assert.False(ok)
+	_, ok = compareNumeric("1", "")
+	assert.False(ok)
+}
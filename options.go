@@ -0,0 +1,163 @@
+package selector
+
+import "unicode"
+
+// Dialect selects the selector grammar accepted by the parser.
+type Dialect int
+
+const (
+	// DialectKubernetes is the default grammar, and matches the kubernetes label selector syntax.
+	DialectKubernetes Dialect = iota
+	// DialectExtended adds the following to the kubernetes grammar:
+	//  - values may be quoted with single or double quotes, e.g. `x == "a, b"`
+	//  - any rune in a value may be escaped with a backslash, e.g. `x == a\,b`
+	//  - disjunctions with `||` or `or`, e.g. `x == a || y == b`
+	//  - grouping with parenthesis, e.g. `(x || y), z`
+	//  - negated groups, e.g. `!(x == a, y)`
+	//  - numeric comparisons with `>`, `>=`, `<` and `<=`, e.g. `cpu-gen >= 4`
+	//  - regular expression matches with `=~` and `!~`, e.g. `app =~ "web-[0-9]+"`
+	//  - glob matches with `like` or `~=`, e.g. `app like web-*` or `app ~= (web-*, api-?)`
+	// Values are not checked against the kubernetes value character set, only against the value length limit.
+	DialectExtended
+)
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case DialectKubernetes:
+		return "kubernetes"
+	case DialectExtended:
+		return "extended"
+	}
+	return "unknown"
+}
+
+// Semantics selects how requirements are matched where this package has historically differed from kubernetes.
+type Semantics int
+
+const (
+	// SemanticsCompatible is the default, and preserves this package's historical behavior;
+	// `in` matches label sets that do not have the key.
+	SemanticsCompatible Semantics = iota
+	// SemanticsK8sStrict matches and validates like kubernetes; `in` requires the key to be present,
+	// and `in` or `notin` must have at least one value.
+	SemanticsK8sStrict
+)
+
+// String returns the name of the semantics.
+func (s Semantics) String() string {
+	switch s {
+	case SemanticsCompatible:
+		return "compatible"
+	case SemanticsK8sStrict:
+		return "k8s-strict"
+	}
+	return "unknown"
+}
+
+// Whitespace selects the runes the parser treats as whitespace between tokens.
+type Whitespace int
+
+const (
+	// WhitespaceASCII is the default, and accepts spaces, tabs, carriage returns and newlines.
+	WhitespaceASCII Whitespace = iota
+	// WhitespaceSpaces only accepts spaces and tabs.
+	WhitespaceSpaces
+	// WhitespaceUnicode accepts any unicode space, e.g. non-breaking spaces.
+	WhitespaceUnicode
+)
+
+// String returns the name of the whitespace rules.
+func (w Whitespace) String() string {
+	switch w {
+	case WhitespaceASCII:
+		return "ascii"
+	case WhitespaceSpaces:
+		return "spaces"
+	case WhitespaceUnicode:
+		return "unicode"
+	}
+	return "unknown"
+}
+
+// Is returns if the rune is whitespace under the rules.
+func (w Whitespace) Is(ch rune) bool {
+	switch w {
+	case WhitespaceSpaces:
+		return ch == Space || ch == Tab
+	case WhitespaceUnicode:
+		return unicode.IsSpace(ch)
+	}
+	return ch == Space || ch == Tab || ch == CarriageReturn || ch == NewLine
+}
+
+// Options configure the parser.
+// The zero value parses the kubernetes dialect with the kubernetes limits; the parser never reads
+// the package level `MaxKeyLen` or `MaxValueLen`, so changing them does not race with parsing.
+type Options struct {
+	// Dialect is the grammar to parse.
+	Dialect Dialect
+	// Semantics are the matching semantics of the parsed requirements.
+	Semantics Semantics
+	// FoldCase compares values with unicode case folding, by wrapping the parsed selector in a `Fold`.
+	FoldCase bool
+	// FoldKeys compares keys, as well as values, with unicode case folding.
+	FoldKeys bool
+	// MaxKeyLen is the maximum length of a key's name, excluding any dns prefix; zero uses 63.
+	MaxKeyLen int
+	// MaxValueLen is the maximum length of a value; zero uses 63.
+	MaxValueLen int
+	// Whitespace are the rules for whitespace between tokens.
+	Whitespace Whitespace
+	// AllowEmpty parses an empty or all whitespace selector as an empty `And`, which matches everything,
+	// rather than returning `ErrEmptySelector`.
+	AllowEmpty bool
+	// Operators restricts the operators accepted, e.g. `[]string{OpEquals, OpIn}`; nil accepts every
+	// operator in the dialect. The `key` and `!key` forms and disjunctions are not restricted.
+	Operators []string
+}
+
+// Extended returns if the options enable the extended dialect.
+func (o Options) Extended() bool {
+	return o.Dialect == DialectExtended
+}
+
+// Strict returns if the options match kubernetes semantics.
+func (o Options) Strict() bool {
+	return o.Semantics == SemanticsK8sStrict
+}
+
+// Folded returns if the options enable case folded comparisons.
+func (o Options) Folded() bool {
+	return o.FoldCase || o.FoldKeys
+}
+
+// Allows returns if the options accept an operator.
+func (o Options) Allows(op string) bool {
+	if o.Operators == nil {
+		return true
+	}
+	for _, allowed := range o.Operators {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// keyLimits returns the total and name length limits for keys.
+func (o Options) keyLimits() (maxTotalLen, maxLen int) {
+	maxLen = o.MaxKeyLen
+	if maxLen == 0 {
+		maxLen = defaultMaxKeyLen
+	}
+	return defaultMaxDNSPrefixLen + maxLen + 1, maxLen
+}
+
+// valueLimit returns the length limit for values.
+func (o Options) valueLimit() int {
+	if o.MaxValueLen == 0 {
+		return defaultMaxValueLen
+	}
+	return o.MaxValueLen
+}
@@ -0,0 +1,71 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestOptionsDialectString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("kubernetes", DialectKubernetes.String())
+	assert.Equal("extended", DialectExtended.String())
+	assert.Equal("unknown", Dialect(-1).String())
+}
+
+func TestOptionsStrict(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(Options{}.Strict())
+	assert.True(Options{Semantics: SemanticsK8sStrict}.Strict())
+	assert.False(Options{Dialect: DialectExtended}.Strict())
+}
+
+func TestOptionsWhitespace(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(WhitespaceASCII.Is('\n'))
+	assert.False(WhitespaceASCII.Is(' '))
+	assert.True(WhitespaceSpaces.Is('\t'))
+	assert.False(WhitespaceSpaces.Is('\n'))
+	assert.True(WhitespaceUnicode.Is(' '))
+	assert.Equal("spaces", WhitespaceSpaces.String())
+}
+
+func TestOptionsAllows(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(Options{}.Allows(OpNotIn))
+	restricted := Options{Operators: []string{OpEquals, OpIn}}
+	assert.True(restricted.Allows(OpIn))
+	assert.False(restricted.Allows(OpNotIn))
+	assert.False(Options{Operators: []string{}}.Allows(OpEquals))
+}
+
+func TestOptionsLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	maxTotalLen, maxLen := Options{}.keyLimits()
+	assert.Equal(317, maxTotalLen)
+	assert.Equal(63, maxLen)
+
+	maxTotalLen, maxLen = Options{MaxKeyLen: 8}.keyLimits()
+	assert.Equal(262, maxTotalLen)
+	assert.Equal(8, maxLen)
+
+	assert.Equal(63, Options{}.valueLimit())
+	assert.Equal(4, Options{MaxValueLen: 4}.valueLimit())
+}
+
+func TestOptionsIgnorePackageLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(maxLen int) { MaxValueLen = maxLen }(MaxValueLen)
+	MaxValueLen = 1
+
+	_, err := Parse("x == abc")
+	assert.Nil(err)
+	assert.True(errors.Is(CheckValue("abc"), ErrValueTooLong))
+}
@@ -0,0 +1,49 @@
+package selector
+
+import "strings"
+
+// Or is a combination selector that matches if any of its children match.
+type Or []Selector
+
+// Matches returns if any of the selectors match the labels.
+func (o Or) Matches(labels Labels) bool {
+	for _, s := range o {
+		if s.Matches(labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates all the selectors in the clause.
+func (o Or) Validate() (err error) {
+	for _, s := range o {
+		err = s.Validate()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// String returns a string representation for the selector.
+func (o Or) String() string {
+	var childValues []string
+	for _, c := range o {
+		childValues = append(childValues, c.String())
+	}
+	return strings.Join(childValues, " || ")
+}
+
+// Children returns the selectors in the clause.
+func (o Or) Children() []Selector {
+	return o
+}
+
+// WithChildren returns a clause of the given selectors, or nil if there are none.
+func (o Or) WithChildren(children []Selector) Selector {
+	if len(children) == 0 {
+		return nil
+	}
+	return Or(children)
+}
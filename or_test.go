@@ -0,0 +1,32 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestOr(t *testing.T) {
+	assert := assert.New(t)
+
+	valid := Labels{
+		"foo": "far",
+		"moo": "lar",
+	}
+	valid2 := Labels{
+		"foo": "bar",
+		"moo": "bar",
+	}
+	invalid := Labels{
+		"foo": "bar",
+		"moo": "mar",
+	}
+
+	selector := Or{Equals{Key: "foo", Value: "far"}, Equals{Key: "moo", Value: "bar"}}
+	assert.True(selector.Matches(valid))
+	assert.True(selector.Matches(valid2))
+	assert.False(selector.Matches(invalid))
+
+	assert.Equal("foo == far || moo == bar", selector.String())
+	assert.Equal("(foo == far || moo == bar), zoo", And{selector, HasKey("zoo")}.String())
+}
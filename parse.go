@@ -22,7 +22,8 @@ package selector
 //
 // Note:
 //  (1) Inclusion - " in " - denotes that the KEY exists and is equal to any of the
-//      VALUEs in its requirement
+//      VALUEs in its requirement. Note that by default this package also matches
+//      label sets where the KEY does not exist; use `SemanticsK8sStrict` to require it.
 //  (2) Exclusion - " notin " - denotes that the KEY is not equal to any
 //      of the VALUEs in its requirement or does not exist
 //  (3) The empty string is a valid VALUE
@@ -30,7 +31,22 @@ package selector
 //      the KEY exists and can be any VALUE.
 //  (5) A requirement with just !KEY requires that the KEY not exist.
 //
+// Errors are returned as a `*ParseError`, which records where in the input the problem was found
+// and wraps a sentinel error, e.g. `ErrInvalidSelector` or `ErrKeyTooLong`; see `ParseError` for the full list.
 func Parse(query string) (Selector, error) {
-	l := &Parser{s: query}
-	return l.Parse()
+	return NewParser(query, Options{}).Parse()
+}
+
+// ParseWithOptions parses a selector using the given options, e.g. to select the extended dialect
+// or to set validation limits for this call only, rather than changing `MaxKeyLen` or `MaxValueLen`.
+func ParseWithOptions(query string, opts Options) (Selector, error) {
+	return NewParser(query, opts).Parse()
+}
+
+// ParseAll parses a selector in recovery mode, returning the requirements that could be parsed
+// and an error for every requirement that could not. After an error the parser resumes at the
+// next top level comma, so a single pass reports every malformed requirement.
+// Use `NewParser(query, opts).ParseAll()` to parse in recovery mode with options.
+func ParseAll(query string) (Selector, []*ParseError) {
+	return NewParser(query, Options{}).ParseAll()
 }
@@ -0,0 +1,101 @@
+package selector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// TokenKey is the description used in `Expected` for a label key.
+	TokenKey = "key"
+	// TokenValue is the description used in `Expected` for a label value.
+	TokenValue = "value"
+	// TokenEnd is the description used in `Expected` for the end of the input.
+	TokenEnd = "end of input"
+)
+
+// ParseError is returned by the parser when a selector is malformed.
+// It wraps one of the sentinel errors so `errors.Is` comparisons against those values continue to work:
+//   - `ErrInvalidSelector`, `ErrInvalidOperator` or `ErrEmptySelector` for structural errors
+//   - `ErrKeyEmpty`, `ErrKeyTooLong`, `ErrKeyDNSPrefixEmpty`, `ErrKeyDNSPrefixTooLong` or `ErrKeyInvalidCharacter` for keys
+//   - `ErrValueTooLong`, `ErrKeyInvalidCharacter`, `ErrValueNotNumeric` or `ErrEmptyValueSet` for values
+//   - `ErrInvalidPattern` or `ErrInvalidGlob` for regular expression and glob patterns
+type ParseError struct {
+	// Err is the underlying sentinel error.
+	Err error
+	// Input is the full selector text that was parsed.
+	Input string
+	// Offset is the byte offset into `Input` where the error begins.
+	Offset int
+	// Column is the 1-based rune column of `Offset`.
+	Column int
+	// Found is the text found at `Offset`; it is empty at the end of the input.
+	Found string
+	// Expected is the set of tokens that would have been valid at `Offset`.
+	Expected []string
+}
+
+// End returns the byte offset just past the offending text.
+func (pe *ParseError) End() int {
+	return pe.Offset + len(pe.Found)
+}
+
+// Error implements error.
+func (pe *ParseError) Error() string {
+	return fmt.Sprintf("%v at column %d: %s", pe.Err, pe.Column, pe.detail())
+}
+
+// Message returns the error without positional information, suitable for display under a caret.
+func (pe *ParseError) Message() string {
+	return fmt.Sprintf("%v: %s", pe.Err, pe.detail())
+}
+
+// detail describes what was found and what was expected.
+func (pe *ParseError) detail() string {
+	var found string
+	if len(pe.Found) == 0 {
+		found = TokenEnd
+	} else {
+		found = strconv.Quote(pe.Found)
+	}
+	if len(pe.Expected) == 0 {
+		return "found " + found
+	}
+	return "found " + found + ", expected " + joinExpected(pe.Expected)
+}
+
+// Unwrap returns the underlying sentinel error.
+func (pe *ParseError) Unwrap() error {
+	return pe.Err
+}
+
+// newParseError returns a parse error for a given input and byte offset.
+func newParseError(err error, input string, offset int, found string, expected ...string) *ParseError {
+	return &ParseError{
+		Err:      err,
+		Input:    input,
+		Offset:   offset,
+		Column:   utf8.RuneCountInString(input[:offset]) + 1,
+		Found:    found,
+		Expected: expected,
+	}
+}
+
+// joinExpected renders a list of expected tokens as english.
+func joinExpected(expected []string) string {
+	quoted := make([]string, len(expected))
+	for index, token := range expected {
+		switch token {
+		case TokenKey, TokenValue, TokenEnd:
+			quoted[index] = token
+		default:
+			quoted[index] = strconv.Quote(token)
+		}
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return "one of " + strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
@@ -0,0 +1,107 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestParseErrorEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Parse("   ")
+	assert.NotNil(err)
+	assert.True(errors.Is(err, ErrEmptySelector))
+
+	var typed *ParseError
+	assert.True(errors.As(err, &typed))
+	assert.Equal(0, typed.Offset)
+	assert.Equal(1, typed.Column)
+	assert.Equal("", typed.Found)
+}
+
+func TestParseErrorInvalidOperator(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Parse("foo == bar, moo !~ lar")
+	assert.NotNil(err)
+	assert.True(errors.Is(err, ErrInvalidOperator))
+
+	var typed *ParseError
+	assert.True(errors.As(err, &typed))
+	assert.Equal("foo == bar, moo !~ lar", typed.Input)
+	assert.Equal(16, typed.Offset)
+	assert.Equal(17, typed.Column)
+	assert.Equal("!~", typed.Found)
+	assert.Equal(18, typed.End())
+	assert.Equal([]string{OpNotEquals}, typed.Expected)
+	assert.Equal(`invalid operator at column 17: found "!~", expected "!="`, typed.Error())
+}
+
+func TestParseErrorInvalidSelector(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Parse("x==a==b")
+	assert.True(errors.Is(err, ErrInvalidSelector))
+	var typed *ParseError
+	assert.True(errors.As(err, &typed))
+	assert.Equal(4, typed.Offset)
+	assert.Equal("=", typed.Found)
+	assert.Equal([]string{",", TokenEnd}, typed.Expected)
+
+	_, err = Parse("x in (foo, bar")
+	assert.True(errors.Is(err, ErrInvalidSelector))
+	assert.True(errors.As(err, &typed))
+	assert.Equal(14, typed.Offset)
+	assert.Equal("", typed.Found)
+	assert.Equal(`invalid selector at column 15: found end of input, expected one of "," or ")"`, typed.Error())
+
+	_, err = Parse("x in foo")
+	assert.True(errors.Is(err, ErrInvalidSelector))
+	assert.True(errors.As(err, &typed))
+	assert.Equal(5, typed.Offset)
+	assert.Equal("f", typed.Found)
+	assert.Equal([]string{"("}, typed.Expected)
+}
+
+func TestParseErrorEmptyKeyFound(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		query  string
+		opts   Options
+		offset int
+		found  string
+	}{
+		{"x,,y", Options{}, 2, ","},
+		{",x", Options{}, 0, ","},
+		{"()", Options{}, 0, "("},
+		{"()", Options{Dialect: DialectExtended}, 1, ")"},
+		{"!!x", Options{}, 1, "!"},
+		{"|| x", Options{Dialect: DialectExtended}, 0, "|"},
+		{"x in (a), ", Options{}, 10, ""},
+	}
+	for _, c := range cases {
+		_, err := ParseWithOptions(c.query, c.opts)
+		assert.True(errors.Is(err, ErrKeyEmpty), c.query)
+		var typed *ParseError
+		assert.True(errors.As(err, &typed), c.query)
+		assert.Equal(c.offset, typed.Offset, c.query)
+		assert.Equal(c.found, typed.Found, c.query)
+	}
+
+	_, err := Parse("x,,y")
+	assert.Equal(`key empty at column 3: found ","`, err.Error())
+}
+
+func TestParseErrorColumnIsRunes(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Parse("함=수 수")
+	var typed *ParseError
+	assert.True(errors.As(err, &typed))
+	assert.Equal(8, typed.Offset)
+	assert.Equal(5, typed.Column)
+	assert.Equal("수", typed.Found)
+}
@@ -1,6 +1,8 @@
 package selector
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/blendlabs/go-assert"
@@ -114,6 +116,17 @@ func TestParseIn(t *testing.T) {
 	assert.False(selector.Matches(invalid), selector.String())
 }
 
+func TestParseInK8sStrict(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseWithOptions("foo in (bar,far)", Options{Semantics: SemanticsK8sStrict})
+	assert.Nil(err)
+	assert.True(selector.Matches(Labels{"foo": "far"}))
+	assert.False(selector.Matches(Labels{"foo": "mar"}))
+	assert.False(selector.Matches(Labels{"zoo": "mar"}))
+	assert.Equal("k8s-strict", SemanticsK8sStrict.String())
+}
+
 func TestParseGroup(t *testing.T) {
 	assert := assert.New(t)
 
@@ -221,3 +234,558 @@ func BenchmarkParse(b *testing.B) {
 		}
 	}
 }
+
+func TestParseEmptyValue(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := Parse("x= ")
+	assert.Nil(err)
+	assert.Equal(Equals{Key: "x", Value: ""}, selector)
+
+	selector, err = Parse("x=,z= ")
+	assert.Nil(err)
+	assert.Equal(And{Equals{Key: "x", Value: ""}, Equals{Key: "z", Value: ""}}, selector)
+}
+
+func TestParseAll(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, errs := ParseAll("x=a, y ~ b, z in (c, d$e), w notin (f), v==g==h, !u")
+	assert.Len(errs, 3)
+	assert.Equal(And{Equals{Key: "x", Value: "a"}, NotIn{Key: "w", Values: []string{"f"}}, NotHasKey("u")}, selector)
+
+	assert.True(errors.Is(errs[0], ErrInvalidOperator))
+	assert.Equal(7, errs[0].Offset)
+	assert.True(errors.Is(errs[1], ErrInvalidSelector))
+	assert.Equal(22, errs[1].Offset)
+	assert.True(errors.Is(errs[2], ErrInvalidSelector))
+	assert.Equal(44, errs[2].Offset)
+}
+
+func TestParseAllValid(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, errs := ParseAll("zoo in (mar,lar,dar),moo,thing == map,!thingy")
+	assert.Empty(errs)
+	expected, err := Parse("zoo in (mar,lar,dar),moo,thing == map,!thingy")
+	assert.Nil(err)
+	assert.Equal(expected, selector)
+}
+
+func TestParseAllInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, errs := ParseAll("")
+	assert.Nil(selector)
+	assert.Len(errs, 1)
+	assert.True(errors.Is(errs[0], ErrEmptySelector))
+
+	selector, errs = ParseAll("x in (a, b, y=1")
+	assert.Nil(selector)
+	assert.Len(errs, 1)
+
+	selector, errs = ParseAll("-x, y, _z")
+	assert.Equal(HasKey("y"), selector)
+	assert.Len(errs, 2)
+	assert.True(errors.Is(errs[0], ErrKeyInvalidCharacter))
+	assert.Equal(0, errs[0].Offset)
+	assert.Equal(7, errs[1].Offset)
+}
+
+func TestParseAllExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := Options{Dialect: DialectExtended}
+	for _, query := range []string{"x || y, z", "(x, y), z", "!(x), y", "x or (y || z), w", "x >= 4, y =~ a.*,"} {
+		expected, err := ParseWithOptions(query, opts)
+		assert.Nil(err, query)
+		selector, errs := NewParser(query, opts).ParseAll()
+		assert.Empty(errs, query)
+		assert.Equal(expected, selector, query)
+	}
+
+	selector, errs := NewParser("x == a, y ~ b || z, (w, v in (c), !u", opts).ParseAll()
+	assert.Len(errs, 2)
+	assert.True(errors.Is(errs[0], ErrInvalidOperator))
+	assert.Equal(10, errs[0].Offset)
+	assert.True(errors.Is(errs[1], ErrInvalidSelector))
+	assert.Equal(Equals{Key: "x", Value: "a"}, selector, "the rest of a failed conjunction is skipped")
+
+	selector, errs = NewParser("x || -y, z", opts).ParseAll()
+	assert.Len(errs, 1)
+	assert.True(errors.Is(errs[0], ErrKeyInvalidCharacter))
+	assert.Equal(Or{HasKey("x"), HasKey("z")}, selector)
+}
+
+func TestParseExtendedQuoted(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+	rules := valueRules{extended: true, maxLen: 63}
+
+	selector, err := ParseWithOptions(`x == "a, b", y != 'c (d)', z in ("e=f", 'g h', ""), w notin ("\"quoted\"")`, extended)
+	assert.Nil(err)
+	assert.Equal(And{
+		Equals{Key: "x", Value: "a, b", rules: rules},
+		NotEquals{Key: "y", Value: "c (d)", rules: rules},
+		In{Key: "z", Values: []string{"e=f", "g h", ""}, rules: rules},
+		NotIn{Key: "w", Values: []string{`"quoted"`}, rules: rules},
+	}, selector)
+	assert.Nil(selector.Validate())
+
+	_, err = Parse(`x == "a, b"`)
+	assert.NotNil(err, "the default dialect should not accept quoted values")
+}
+
+func TestParseExtendedEscaped(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	rules := valueRules{extended: true, maxLen: 63}
+
+	selector, err := ParseWithOptions(`x=\,, y=a\ b, z in (c\,d, e\)), w=f\\g`, extended)
+	assert.Nil(err)
+	assert.Equal(And{
+		Equals{Key: "x", Value: ",", rules: rules},
+		Equals{Key: "y", Value: "a b", rules: rules},
+		In{Key: "z", Values: []string{"c,d", "e)"}, rules: rules},
+		Equals{Key: "w", Value: `f\g`},
+	}, selector)
+}
+
+func TestParseExtendedLiteralBackslash(t *testing.T) {
+	assert := assert.New(t)
+
+	// a backslash before a rune that isn't syntax is literal, as in the default dialect.
+	for _, opts := range []Options{{}, {Dialect: DialectExtended}} {
+		selector, err := ParseWithOptions(`x == a\b`, opts)
+		assert.Nil(err)
+		assert.Equal(Equals{Key: "x", Value: `a\b`}, selector)
+	}
+
+	selector, err := ParseWithOptions(`y in (c\d, e)`, Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	assert.Equal(In{Key: "y", Values: []string{`c\d`, "e"}}, selector)
+}
+
+func TestStringRoundTripDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	selectors := []Selector{
+		Equals{Key: "x", Value: "a"},
+		Equals{Key: "x", Value: ""},
+		NotEquals{Key: "x", Value: "a.b-c_d"},
+		In{Key: "x", Values: []string{"a", "c"}},
+		NotIn{Key: "x", Values: []string{"a", "b"}},
+		And{Equals{Key: "x", Value: "a"}, HasKey("y"), NotHasKey("z")},
+	}
+	for _, s := range selectors {
+		parsed, err := Parse(s.String())
+		assert.Nil(err, s.String())
+		assert.Equal(s, parsed, s.String())
+
+		parsed, err = ParseWithOptions(s.String(), Options{Dialect: DialectExtended})
+		assert.Nil(err, s.String())
+		assert.Equal(s, parsed, s.String())
+	}
+}
+
+func TestParseExtendedInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	_, err := ParseWithOptions(`x == "a, b`, extended)
+	assert.True(errors.Is(err, ErrInvalidSelector))
+	var typed *ParseError
+	assert.True(errors.As(err, &typed))
+	assert.Equal(5, typed.Offset)
+	assert.Equal([]string{`"`}, typed.Expected)
+
+	_, err = ParseWithOptions(`x == a\`, extended)
+	assert.True(errors.Is(err, ErrInvalidSelector))
+
+	_, err = ParseWithOptions(`x in ('a', 'b'`, extended)
+	assert.True(errors.Is(err, ErrInvalidSelector))
+
+	_, err = ParseWithOptions(`x == "`+strings.Repeat("a", MaxValueLen+1)+`"`, extended)
+	assert.True(errors.Is(err, ErrValueTooLong))
+}
+
+func TestParseExtendedRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	selectors := []Selector{
+		Equals{Key: "x", Value: "a, b"},
+		Equals{Key: "x", Value: `a\b"c`},
+		Equals{Key: "x", Value: `a\b`},
+		Equals{Key: "x", Value: `y\`},
+		Equals{Key: "x", Value: `a\\b`},
+		NotIn{Key: "x", Values: []string{"a", `b\c`}},
+		NotEquals{Key: "x", Value: "(a) = b"},
+		In{Key: "x", Values: []string{"a b", "", "c,d", "web-1"}},
+		NotIn{Key: "x", Values: []string{"'a'", "b.c"}},
+		And{Equals{Key: "x", Value: "a b"}, HasKey("y"), NotHasKey("z")},
+	}
+
+	for _, selector := range selectors {
+		parsed, err := ParseWithOptions(selector.String(), extended)
+		assert.Nil(err, selector.String())
+		assert.True(EqualSelectors(selector, parsed), selector.String())
+		assert.Equal(selector.String(), parsed.String())
+		assert.Nil(parsed.Validate(), selector.String())
+	}
+}
+
+func TestParseInNameSymbols(t *testing.T) {
+	assert := assert.New(t)
+
+	// the default dialect only accepts letters and digits in a set.
+	_, err := Parse("x in (web-1, api.v2, db_3)")
+	assert.True(errors.Is(err, ErrInvalidSelector))
+
+	selector, err := ParseWithOptions("x in (web-1, api.v2, db_3)", Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	assert.Equal(In{Key: "x", Values: []string{"web-1", "api.v2", "db_3"}}, selector)
+}
+
+func TestParseExtendedOr(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	selector, err := ParseWithOptions("x=a, y || z in (b, c) or !w", extended)
+	assert.Nil(err)
+	assert.Equal(Or{
+		And{Equals{Key: "x", Value: "a"}, HasKey("y")},
+		In{Key: "z", Values: []string{"b", "c"}},
+		NotHasKey("w"),
+	}, selector)
+
+	selector, err = ParseWithOptions("x=a||y=b", extended)
+	assert.Nil(err)
+	assert.Equal(Or{Equals{Key: "x", Value: "a"}, Equals{Key: "y", Value: "b"}}, selector)
+
+	_, err = Parse("x=a||y=b")
+	assert.NotNil(err, "the default dialect should not accept disjunctions")
+}
+
+func TestParseExtendedGrouping(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	selector, err := ParseWithOptions("(x || y), (z=a or (w, v != b)), u", extended)
+	assert.Nil(err)
+	assert.Equal(And{
+		Or{HasKey("x"), HasKey("y")},
+		Or{Equals{Key: "z", Value: "a"}, And{HasKey("w"), NotEquals{Key: "v", Value: "b"}}},
+		HasKey("u"),
+	}, selector)
+
+	assert.True(selector.Matches(Labels{"x": "", "w": "", "u": ""}))
+	assert.False(selector.Matches(Labels{"x": "", "w": "", "v": "b", "u": ""}))
+
+	selector, err = ParseWithOptions("((x, y)), z", extended)
+	assert.Nil(err)
+	assert.Equal(And{HasKey("x"), HasKey("y"), HasKey("z")}, selector)
+
+	badStrings := []string{
+		"(x",
+		"x)",
+		"()",
+		"x ||",
+		"x | y",
+		"x, (y || z",
+		"x = a b",
+	}
+	for _, str := range badStrings {
+		_, err = ParseWithOptions(str, extended)
+		assert.NotNil(err, str)
+	}
+}
+
+func TestParseExtendedOrRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	queries := []string{
+		"x == a || y == b",
+		"(x == a || y == b), z",
+		"x, y || z in (a, b), w != \"c d\"",
+		"(x || y), (z || w)",
+	}
+	for _, query := range queries {
+		selector, err := ParseWithOptions(query, extended)
+		assert.Nil(err, query)
+		assert.Equal(query, selector.String())
+		reparsed, err := ParseWithOptions(selector.String(), extended)
+		assert.Nil(err, query)
+		assert.Equal(selector, reparsed)
+	}
+}
+
+func TestParseExtendedNot(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	selector, err := ParseWithOptions("!(x=a, y in (b, c)), !z", extended)
+	assert.Nil(err)
+	assert.Equal(And{
+		Not{Selector: And{Equals{Key: "x", Value: "a"}, In{Key: "y", Values: []string{"b", "c"}}}},
+		NotHasKey("z"),
+	}, selector)
+
+	assert.True(selector.Matches(Labels{"x": "b"}))
+	assert.False(selector.Matches(Labels{"x": "a"}))
+	assert.False(selector.Matches(Labels{"x": "b", "z": "c"}))
+
+	selector, err = ParseWithOptions("x || ! (y || z)", extended)
+	assert.Nil(err)
+	assert.Equal(Or{HasKey("x"), Not{Selector: Or{HasKey("y"), HasKey("z")}}}, selector)
+
+	reparsed, err := ParseWithOptions(selector.String(), extended)
+	assert.Nil(err)
+	assert.Equal(selector, reparsed)
+
+	_, err = Parse("!(x)")
+	assert.NotNil(err, "the default dialect should not accept negated groups")
+	_, err = ParseWithOptions("!(x", extended)
+	assert.NotNil(err)
+}
+
+func TestParseExtendedNumeric(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	selector, err := ParseWithOptions("cpu-gen>4,cpu-gen <= 6, mem >= -1.5, disk<10", extended)
+	assert.Nil(err)
+	assert.Equal(And{
+		GreaterThan{Key: "cpu-gen", Value: "4"},
+		LessThanOrEqual{Key: "cpu-gen", Value: "6"},
+		GreaterThanOrEqual{Key: "mem", Value: "-1.5"},
+		LessThan{Key: "disk", Value: "10"},
+	}, selector)
+
+	assert.True(selector.Matches(Labels{"cpu-gen": "5", "mem": "0", "disk": "9"}))
+	assert.False(selector.Matches(Labels{"cpu-gen": "7", "mem": "0", "disk": "9"}))
+
+	reparsed, err := ParseWithOptions(selector.String(), extended)
+	assert.Nil(err)
+	assert.Equal(selector, reparsed)
+
+	_, err = ParseWithOptions("x > four", extended)
+	assert.True(errors.Is(err, ErrValueNotNumeric))
+	var typed *ParseError
+	assert.True(errors.As(err, &typed))
+	assert.Equal(4, typed.Offset)
+	assert.Equal("four", typed.Found)
+
+	_, err = ParseWithOptions("x >", extended)
+	assert.True(errors.Is(err, ErrValueNotNumeric))
+
+	_, err = ParseWithOptions("x => 1", extended)
+	assert.True(errors.Is(err, ErrInvalidOperator))
+
+	_, err = Parse("x>1")
+	assert.NotNil(err, "the default dialect should not accept numeric comparisons")
+}
+
+func TestParseExtendedMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	selector, err := ParseWithOptions(`app =~ "web-[0-9]+|api", env!~dev.*`, extended)
+	assert.Nil(err)
+	typed, isTyped := selector.(And)
+	assert.True(isTyped)
+	assert.Len(typed, 2)
+	matches, isMatches := typed[0].(Matches)
+	assert.True(isMatches)
+	assert.Equal("app", matches.Key)
+	assert.Equal("web-[0-9]+|api", matches.Pattern)
+	assert.NotNil(matches.compiled)
+	notMatches, isNotMatches := typed[1].(NotMatches)
+	assert.True(isNotMatches)
+	assert.Equal("env", notMatches.Key)
+	assert.Equal("dev.*", notMatches.Pattern)
+
+	assert.True(selector.Matches(Labels{"app": "web-1", "env": "prod"}))
+	assert.False(selector.Matches(Labels{"app": "web-1", "env": "dev-2"}))
+
+	reparsed, err := ParseWithOptions(selector.String(), extended)
+	assert.Nil(err)
+	assert.Equal(selector.String(), reparsed.String())
+
+	_, err = ParseWithOptions(`app =~ "web-("`, extended)
+	assert.True(errors.Is(err, ErrInvalidPattern))
+	var parseError *ParseError
+	assert.True(errors.As(err, &parseError))
+	assert.Equal(7, parseError.Offset)
+
+	_, err = Parse("app=~web")
+	assert.NotNil(err, "the default dialect should not accept regular expressions")
+}
+
+func TestParseExtendedLike(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+
+	selector, err := ParseWithOptions("app like web-*, tier ~= (front-?, [a-c]*), env like 'prod (eu)*'", extended)
+	assert.Nil(err)
+	assert.Equal(And{
+		Like{Key: "app", Patterns: []string{"web-*"}},
+		Like{Key: "tier", Patterns: []string{"front-?", "[a-c]*"}},
+		Like{Key: "env", Patterns: []string{"prod (eu)*"}},
+	}, selector)
+
+	assert.True(selector.Matches(Labels{"app": "web-1", "tier": "backend", "env": "prod (eu)-1"}))
+	assert.False(selector.Matches(Labels{"app": "web-1", "tier": "data", "env": "prod (eu)-1"}))
+
+	reparsed, err := ParseWithOptions(selector.String(), extended)
+	assert.Nil(err)
+	assert.Equal(selector, reparsed)
+
+	_, err = ParseWithOptions("app like web-[", extended)
+	assert.True(errors.Is(err, ErrInvalidGlob))
+	assert.False(errors.Is(err, ErrInvalidPattern))
+	_, err = ParseWithOptions("app lik web-*", extended)
+	assert.True(errors.Is(err, ErrInvalidOperator))
+	_, err = ParseWithOptions("app ~ web-*", extended)
+	assert.True(errors.Is(err, ErrInvalidOperator))
+	_, err = Parse("app like web-*")
+	assert.NotNil(err, "the default dialect should not accept globs")
+}
+
+func TestParseFoldCase(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseWithOptions("app in (Web, API), env != Dev", Options{FoldCase: true})
+	assert.Nil(err)
+	_, isFold := selector.(Fold)
+	assert.True(isFold)
+	assert.True(selector.Matches(Labels{"app": "web", "env": "prod"}))
+	assert.True(selector.Matches(Labels{"app": "api", "env": "prod"}))
+	assert.False(selector.Matches(Labels{"app": "api", "env": "DEV"}))
+	assert.Equal("app in (Web, API), env != Dev", selector.String())
+
+	selector, err = ParseWithOptions("함=수, App", Options{FoldKeys: true})
+	assert.Nil(err)
+	assert.True(selector.Matches(Labels{"함": "수", "app": ""}))
+
+	selector, err = ParseWithOptions(`app =~ "web-[a-z]+"`, Options{Dialect: DialectExtended, FoldCase: true})
+	assert.Nil(err)
+	assert.True(selector.Matches(Labels{"app": "WEB-A"}))
+
+	selector, err = Parse("app == Web")
+	assert.Nil(err)
+	assert.False(selector.Matches(Labels{"app": "web"}))
+}
+
+func TestParseWithOptionsLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ParseWithOptions("abcdefghi == a", Options{MaxKeyLen: 8})
+	assert.NotNil(err)
+	assert.True(errors.Is(err, ErrKeyTooLong))
+	_, err = ParseWithOptions("example.com/abcdefgh == a", Options{MaxKeyLen: 8})
+	assert.Nil(err)
+
+	_, err = ParseWithOptions("x in (abc, abcde)", Options{MaxValueLen: 4})
+	assert.True(errors.Is(err, ErrValueTooLong))
+	assert.Equal(`value too long at column 12: found "abcde"`, err.Error())
+	_, err = ParseWithOptions(`x == "abcde"`, Options{Dialect: DialectExtended, MaxValueLen: 4})
+	assert.True(errors.Is(err, ErrValueTooLong))
+	_, err = ParseWithOptions("x > 12345", Options{Dialect: DialectExtended, MaxValueLen: 4})
+	assert.True(errors.Is(err, ErrValueTooLong))
+
+	long := strings.Repeat("a", MaxValueLen+1)
+	_, err = ParseWithOptions("x == "+long, Options{MaxValueLen: MaxValueLen + 1})
+	assert.Nil(err)
+	_, err = Parse("x == " + long)
+	assert.True(errors.Is(err, ErrValueTooLong))
+}
+
+func TestParseWithOptionsWhitespace(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ParseWithOptions("x == a,\ny", Options{Whitespace: WhitespaceSpaces})
+	assert.NotNil(err)
+	_, err = ParseWithOptions("x == a,\ty", Options{Whitespace: WhitespaceSpaces})
+	assert.Nil(err)
+
+	_, err = Parse("x ==\u00a0a")
+	assert.NotNil(err)
+	selector, err := ParseWithOptions("x ==\u00a0a, y", Options{Whitespace: WhitespaceUnicode})
+	assert.Nil(err)
+	assert.Equal("x == a, y", selector.String())
+}
+
+func TestParseWithOptionsAllowEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseWithOptions("  ", Options{AllowEmpty: true})
+	assert.Nil(err)
+	assert.True(selector.Matches(Labels{"foo": "bar"}))
+	assert.True(selector.Matches(Labels{}))
+	assert.Equal("", selector.String())
+
+	selector, errs := NewParser("", Options{AllowEmpty: true}).ParseAll()
+	assert.Empty(errs)
+	assert.True(selector.Matches(Labels{}))
+
+	_, err = ParseWithOptions("  ", Options{})
+	assert.True(errors.Is(err, ErrEmptySelector))
+}
+
+func TestParseWithOptionsOperators(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := Options{Operators: []string{OpEquals, OpIn}}
+	selector, err := ParseWithOptions("x = a, y in (b), z, !w", opts)
+	assert.Nil(err)
+	assert.Equal("x == a, y in (b), z, !w", selector.String())
+
+	_, err = ParseWithOptions("x = a, y notin (b)", opts)
+	assert.NotNil(err)
+	assert.True(errors.Is(err, ErrInvalidOperator))
+	var parseErr *ParseError
+	assert.True(errors.As(err, &parseErr))
+	assert.Equal(9, parseErr.Offset)
+	assert.Equal("notin", parseErr.Found)
+	assert.Equal([]string{OpEquals, OpIn}, parseErr.Expected)
+
+	_, err = ParseWithOptions("x >= 4", Options{Dialect: DialectExtended, Operators: []string{OpGreaterThan}})
+	assert.True(errors.Is(err, ErrInvalidOperator))
+}
+
+func TestParseK8sStrictSemantics(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := Options{Semantics: SemanticsK8sStrict}
+	selector, err := ParseWithOptions("foo in (bar), moo notin (lar)", opts)
+	assert.Nil(err)
+	assert.False(selector.Matches(Labels{"moo": "mar"}))
+	assert.True(selector.Matches(Labels{"foo": "bar", "moo": "mar"}))
+
+	_, err = ParseWithOptions("foo in ()", opts)
+	assert.True(errors.Is(err, ErrEmptyValueSet))
+	_, err = ParseWithOptions("foo notin ( )", opts)
+	assert.True(errors.Is(err, ErrEmptyValueSet))
+	var parseErr *ParseError
+	assert.True(errors.As(err, &parseErr))
+	assert.Equal("( )", parseErr.Found)
+
+	_, err = Parse("foo notin ()")
+	assert.Nil(err)
+	_, err = ParseWithOptions("foo > 1", opts)
+	assert.NotNil(err)
+}
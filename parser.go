@@ -1,6 +1,7 @@
 package selector
 
 import (
+	"regexp"
 	"strings"
 	"unicode/utf8"
 )
@@ -16,6 +17,30 @@ const (
 	OpIn = "in"
 	// OpNotIn is an operator.
 	OpNotIn = "notin"
+	// OpGreaterThan is an operator in the extended dialect.
+	OpGreaterThan = ">"
+	// OpGreaterThanOrEqual is an operator in the extended dialect.
+	OpGreaterThanOrEqual = ">="
+	// OpLessThan is an operator in the extended dialect.
+	OpLessThan = "<"
+	// OpLessThanOrEqual is an operator in the extended dialect.
+	OpLessThanOrEqual = "<="
+	// OpMatches is an operator in the extended dialect.
+	OpMatches = "=~"
+	// OpNotMatches is an operator in the extended dialect.
+	OpNotMatches = "!~"
+	// OpLike is an operator in the extended dialect.
+	OpLike = "like"
+	// OpLikeSymbol is the symbolic form of `OpLike` in the extended dialect.
+	OpLikeSymbol = "~="
+	// OpExists is the operator of a `HasKey` requirement in a `Requirement`; it is not part of the grammar.
+	OpExists = "exists"
+	// OpDoesNotExist is the operator of a `NotHasKey` requirement in a `Requirement`, i.e. `!key`.
+	OpDoesNotExist = "!"
+	// OpOr is the disjunction operator in the extended dialect.
+	OpOr = "||"
+	// OpOrKeyword is the keyword form of the disjunction operator in the extended dialect.
+	OpOrKeyword = "or"
 )
 
 // Parser parses a selector incrementally.
@@ -26,121 +51,544 @@ type Parser struct {
 	pos int
 	// m is an optional mark
 	m int
+	// opts are the parser options
+	opts Options
+	// tree records the syntax tree while parsing, when set
+	tree bool
+	// node is the syntax tree node for the last selector read
+	node *Node
+	// key, op and values are the spans of the requirement being read
+	key, op Span
+	values  []Span
+}
+
+// NewParser returns a parser for a query with the given options.
+func NewParser(query string, opts Options) *Parser {
+	return &Parser{s: query, opts: opts}
 }
 
 // Parse does the actual parsing.
 func (p *Parser) Parse() (Selector, error) {
-	p.s = strings.TrimSpace(p.s)
-	if len(p.s) == 0 {
-		return nil, ErrEmptySelector
+	selector, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return p.fold(selector), nil
+}
+
+// parse parses the selector in the configured dialect.
+func (p *Parser) parse() (Selector, error) {
+	p.skipWhiteSpace()
+	if p.done() {
+		if p.opts.AllowEmpty {
+			return And{}, nil
+		}
+		return nil, newParseError(ErrEmptySelector, p.s, 0, "", TokenKey)
+	}
+	if p.opts.Extended() {
+		return p.parseExtended()
 	}
 
 	var b rune
 	var selector Selector
+	var subSelector Selector
 	var err error
-	var op string
+
+	var node *Node
 
 	// loop over "clauses"
 	for {
+		subSelector, err = p.readRequirement()
+		if err != nil {
+			return nil, err
+		}
+		selector = p.lift(selector, subSelector)
+		node = p.liftNode(node, p.node, selector)
 
-		// sniff the !haskey form
-		b = p.current()
-		if b == Bang {
-			p.advance() // we aren't going to use the '!'
-			selector = p.lift(selector, p.notHasKey(p.readWord()))
+		b = p.skipToComma()
+		if b == Comma {
+			p.advance()
 			if p.done() {
 				break
 			}
 			continue
 		}
 
-		// we're done peeking the first char
-		key := p.readWord()
+		// these two are effectively the same
+		if p.isTerminator(b) || p.done() {
+			break
+		}
+
+		return nil, p.errorAt(ErrInvalidSelector, p.pos, string(Comma), TokenEnd)
+	}
 
-		p.mark()
-		b = p.skipToComma()
-		if b == Comma || p.isTerminator(b) || p.done() {
-			selector = p.lift(selector, p.hasKey(key))
-			p.advance()
-			if p.done() {
-				break
+	p.node = node
+	return selector, nil
+}
+
+// parseExtended parses the extended grammar, which adds disjunctions and grouping:
+//
+//	<expression>  ::= <conjunction> | <conjunction> <or> <expression>
+//	<or>          ::= "||" | "or"
+//	<conjunction> ::= <term> | <term> "," <conjunction>
+//	<term>        ::= ["!"] "(" <expression> ")" | <requirement>
+//
+// `,` binds tighter than `||`, e.g. `a, b || c` is `(a, b) || c`.
+func (p *Parser) parseExtended() (Selector, error) {
+	selector, err := p.readExpression(0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipWhiteSpace()
+	if !p.done() {
+		return nil, p.errorAt(ErrInvalidSelector, p.pos, string(Comma), OpOr, OpOrKeyword, TokenEnd)
+	}
+	return selector, nil
+}
+
+// readExpression reads a disjunction of conjunctions.
+func (p *Parser) readExpression(depth int) (Selector, error) {
+	var terms Or
+	var nodes []*Node
+	var span Span
+	for {
+		conjunction, err := p.readConjunction(depth)
+		if err != nil {
+			return nil, err
+		}
+		if p.tree {
+			if len(nodes) == 0 {
+				span.Start = p.node.Span.Start
+			}
+			span.End = p.node.Span.End
+		}
+		if typed, isTyped := conjunction.(Or); isTyped {
+			terms = append(terms, typed...)
+			if p.tree {
+				nodes = append(nodes, p.node.Children...)
 			}
-			continue
 		} else {
-			p.popMark()
+			terms = append(terms, conjunction)
+			nodes = append(nodes, p.node)
 		}
+		if !p.readOr() {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		p.node = nodes[0]
+		return terms[0], nil
+	}
+	if p.tree {
+		p.node = &Node{Selector: terms, Span: span, Children: nodes, source: p.s}
+	}
+	return terms, nil
+}
 
-		op, err = p.readOp()
+// readConjunction reads a comma separated list of terms.
+func (p *Parser) readConjunction(depth int) (Selector, error) {
+	var selector Selector
+	var node *Node
+	for {
+		term, err := p.readTerm(depth)
 		if err != nil {
 			return nil, err
 		}
+		selector = p.lift(selector, term)
+		node = p.liftNode(node, p.node, selector)
+		p.node = node
+
+		p.skipWhiteSpace()
+		if p.current() != Comma {
+			return selector, nil
+		}
+		p.advance()
 
-		var subSelector Selector
-		switch op {
-		case OpEquals, OpDoubleEquals:
-			subSelector, err = p.equals(key)
+		// allow a trailing comma, as the kubernetes dialect does
+		p.skipWhiteSpace()
+		if depth == 0 && p.done() {
+			return selector, nil
+		}
+	}
+}
+
+// readTerm reads a parenthesized expression, a negated parenthesized expression, or a single requirement.
+func (p *Parser) readTerm(depth int) (Selector, error) {
+	p.skipWhiteSpace()
+	if p.done() {
+		return nil, p.errorAt(ErrInvalidSelector, p.pos, TokenKey, string(OpenParens))
+	}
+	if p.current() == Bang {
+		start := p.pos
+		p.advance()
+		p.skipWhiteSpace()
+		if p.current() == OpenParens {
+			group, err := p.readGroup(depth)
 			if err != nil {
 				return nil, err
 			}
-			selector = p.lift(selector, subSelector)
-		case OpNotEquals:
-			subSelector, err = p.notEquals(key)
-			if err != nil {
-				return nil, err
+			selector := Not{Selector: group}
+			if p.tree {
+				p.node = &Node{Selector: selector, Span: Span{start, p.pos}, Operator: Span{start, start + 1}, Children: []*Node{p.node}, source: p.s}
 			}
-			selector = p.lift(selector, subSelector)
-		case OpIn:
-			subSelector, err = p.in(key)
-			if err != nil {
-				return nil, err
+			return selector, nil
+		}
+		p.pos = start // the !haskey form is read as a requirement
+	}
+	if p.current() != OpenParens {
+		return p.readRequirement()
+	}
+	return p.readGroup(depth)
+}
+
+// readGroup reads a parenthesized expression.
+func (p *Parser) readGroup(depth int) (Selector, error) {
+	start := p.pos
+	p.advance() // skip the open paren
+	selector, err := p.readExpression(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	p.skipWhiteSpace()
+	if p.current() != CloseParens {
+		return nil, p.errorAt(ErrInvalidSelector, p.pos, string(Comma), OpOr, OpOrKeyword, string(CloseParens))
+	}
+	p.advance() // skip the close paren
+	if p.tree && len(p.node.Children) > 0 {
+		p.node.Span = Span{start, p.pos} // combinations include their parenthesis
+	}
+	return selector, nil
+}
+
+// readOr consumes a disjunction operator if one is next, returning if it did.
+func (p *Parser) readOr() bool {
+	p.skipWhiteSpace()
+	if !p.isOrNext() {
+		return false
+	}
+	p.pos += 2 // both `||` and `or` are two bytes
+	return true
+}
+
+// isOrNext returns if the cursor is on a disjunction operator.
+func (p *Parser) isOrNext() bool {
+	if strings.HasPrefix(p.s[p.pos:], OpOr) {
+		return true
+	}
+	if !strings.HasPrefix(p.s[p.pos:], OpOrKeyword) {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(p.s[p.pos+len(OpOrKeyword):])
+	return p.isWhitespace(next) || next == OpenParens || next == Bang
+}
+
+// ParseAll parses every requirement it can, recording each error and resynchronizing at the next
+// top level comma rather than stopping at the first error.
+// It returns the requirements that parsed (nil if there were none) and every error, in input order.
+// In the extended dialect disjunctions and groups are read as `Parse` reads them.
+func (p *Parser) ParseAll() (Selector, []*ParseError) {
+	p.skipWhiteSpace()
+	if p.done() {
+		if p.opts.AllowEmpty {
+			return p.fold(And{}), nil
+		}
+		return nil, []*ParseError{newParseError(ErrEmptySelector, p.s, 0, "", TokenKey)}
+	}
+	if p.opts.Extended() {
+		return p.parseAllExtended()
+	}
+
+	var b rune
+	var start int
+	var selector Selector
+	var subSelector Selector
+	var err error
+	var errs []*ParseError
+
+	// loop over "clauses"
+	for {
+		start = p.pos
+		subSelector, err = p.readRequirement()
+		if err == nil {
+			b = p.skipToComma()
+			if b != Comma && !p.isTerminator(b) && !p.done() {
+				err = p.errorAt(ErrInvalidSelector, p.pos, string(Comma), TokenEnd)
 			}
+		}
+
+		if err != nil {
+			errs = append(errs, p.asParseError(err))
+			p.resync(start)
+		} else {
 			selector = p.lift(selector, subSelector)
-		case OpNotIn:
-			subSelector, err = p.notIn(key)
-			if err != nil {
-				return nil, err
+		}
+
+		if p.done() {
+			break
+		}
+		p.advance() // skip the comma
+		if p.done() {
+			break
+		}
+	}
+
+	if selector == nil {
+		return nil, errs
+	}
+	return p.fold(selector), errs
+}
+
+// parseAllExtended parses the extended grammar in recovery mode. A term that fails to parse is dropped
+// from its conjunction, and parsing resumes at the next top level comma.
+func (p *Parser) parseAllExtended() (Selector, []*ParseError) {
+	var terms Or
+	var conjunction Selector
+	var errs []*ParseError
+
+	addTerm := func(s Selector) {
+		if typed, isTyped := s.(Or); isTyped {
+			terms = append(terms, typed...)
+			return
+		}
+		terms = append(terms, s)
+	}
+
+	for {
+		start := p.pos
+		term, err := p.readTerm(0)
+		if err == nil {
+			p.skipWhiteSpace()
+			if !p.done() && p.current() != Comma && !p.isOrNext() {
+				err = p.errorAt(ErrInvalidSelector, p.pos, string(Comma), OpOr, OpOrKeyword, TokenEnd)
 			}
-			selector = p.lift(selector, subSelector)
-		default:
-			return nil, ErrInvalidOperator
 		}
 
-		b = p.skipToComma()
-		if b == Comma {
-			p.advance()
-			if p.done() {
-				break
+		if err != nil {
+			errs = append(errs, p.asParseError(err))
+			p.resync(start)
+		} else {
+			conjunction = p.lift(conjunction, term)
+			if p.readOr() {
+				addTerm(conjunction)
+				conjunction = nil
+				continue
 			}
-			continue
 		}
 
-		// these two are effectively the same
-		if p.isTerminator(b) || p.done() {
+		if p.done() {
+			break
+		}
+		p.advance() // skip the comma
+		p.skipWhiteSpace()
+		if p.done() {
 			break
 		}
+	}
+	if conjunction != nil {
+		addTerm(conjunction)
+	}
+
+	switch len(terms) {
+	case 0:
+		return nil, errs
+	case 1:
+		return p.fold(terms[0]), errs
+	}
+	return p.fold(terms), errs
+}
+
+// fold wraps the selector in a `Fold` if the options enable case folding.
+func (p *Parser) fold(selector Selector) Selector {
+	if !p.opts.Folded() {
+		return selector
+	}
+	return NewFold(selector, p.opts.FoldKeys)
+}
+
+// resync moves the cursor to the first comma at or after the cursor that is not nested in parenthesis,
+// counting nesting from the start of the current requirement, or to the end of the input.
+func (p *Parser) resync(start int) {
+	failed := p.pos
+	p.pos = start
+
+	var depth int
+	var ch rune
+	for !p.done() {
+		ch = p.current()
+		if p.opts.Extended() {
+			if ch == BackSlash {
+				p.advance()
+				p.advance()
+				continue
+			}
+			if p.isQuote(ch) {
+				if _, err := p.readQuoted(); err != nil {
+					p.pos = len(p.s)
+				}
+				continue
+			}
+		}
+		switch ch {
+		case OpenParens:
+			depth++
+		case CloseParens:
+			if depth > 0 {
+				depth--
+			}
+		case Comma:
+			if depth == 0 && p.pos >= failed {
+				return
+			}
+		}
+		p.advance()
+	}
+}
+
+// asParseError coerces an error into a parse error at the cursor.
+func (p *Parser) asParseError(err error) *ParseError {
+	if typed, isTyped := err.(*ParseError); isTyped {
+		return typed
+	}
+	return p.errorAt(err, p.pos)
+}
+
+// readRequirement reads a single requirement, leaving the cursor after the requirement.
+func (p *Parser) readRequirement() (Selector, error) {
+	p.skipWhiteSpace()
+	start := p.pos
+	p.key, p.op, p.values = Span{}, Span{}, nil
+
+	selector, err := p.readLeaf()
+	if err != nil || !p.tree {
+		return selector, err
+	}
+	end := p.pos
+	switch selector.(type) {
+	case HasKey, NotHasKey:
+		end = p.key.End // the cursor may be past the following whitespace
+	}
+	p.node = &Node{Selector: selector, Span: Span{start, end}, Key: p.key, Operator: p.op, Values: p.values, source: p.s}
+	return selector, nil
+}
 
-		return nil, ErrInvalidSelector
+// readLeaf reads the key, operator and values of a requirement.
+func (p *Parser) readLeaf() (Selector, error) {
+	// sniff the !haskey form
+	if p.current() == Bang {
+		p.op = Span{p.pos, p.pos + 1}
+		p.advance() // we aren't going to use the '!'
+		key, err := p.readKey()
+		if err != nil {
+			return nil, err
+		}
+		return p.notHasKey(key), nil
 	}
 
-	err = selector.Validate()
+	// we're done peeking the first char
+	key, err := p.readKey()
 	if err != nil {
 		return nil, err
 	}
 
-	return selector, nil
+	p.mark()
+	b := p.skipToComma()
+	if b == Comma || p.isTerminator(b) || p.done() || p.isGroupEnd(b) {
+		p.op = Span{p.key.End, p.key.End}
+		return p.hasKey(key), nil
+	}
+	p.popMark()
+
+	p.skipWhiteSpace()
+	opStart := p.pos
+	op, err := p.readOp()
+	if err != nil {
+		return nil, err
+	}
+	if !p.opts.Allows(op) {
+		return nil, newParseError(ErrInvalidOperator, p.s, opStart, op, p.operators()...)
+	}
+	p.op = Span{opStart, p.pos}
+
+	switch op {
+	case OpEquals, OpDoubleEquals:
+		return p.equals(key)
+	case OpNotEquals:
+		return p.notEquals(key)
+	case OpIn:
+		return p.in(key)
+	case OpNotIn:
+		return p.notIn(key)
+	case OpGreaterThan, OpGreaterThanOrEqual, OpLessThan, OpLessThanOrEqual:
+		return p.compare(key, op)
+	case OpLike, OpLikeSymbol:
+		return p.like(key)
+	case OpMatches:
+		return p.matches(key)
+	case OpNotMatches:
+		return p.notMatches(key)
+	}
+	return nil, p.errorAt(ErrInvalidOperator, opStart, p.operators()...)
+}
+
+// operators returns the operators valid in the parser's dialect that the options allow.
+func (p *Parser) operators() []string {
+	all := []string{OpEquals, OpDoubleEquals, OpNotEquals, OpIn, OpNotIn}
+	if p.opts.Extended() {
+		all = append(all, OpGreaterThan, OpGreaterThanOrEqual, OpLessThan, OpLessThanOrEqual, OpMatches, OpNotMatches, OpLike, OpLikeSymbol)
+	}
+	var allowed []string
+	for _, op := range all {
+		if p.opts.Allows(op) {
+			allowed = append(allowed, op)
+		}
+	}
+	return allowed
 }
 
 // lift starts grouping selectors into a high level `and`, returning the aggregate selector.
+// Grouped conjunctions are flattened into the aggregate.
 func (p *Parser) lift(current, next Selector) Selector {
 	if current == nil {
 		return next
 	}
+	if typed, isTyped := next.(And); isTyped {
+		for _, child := range typed {
+			current = p.lift(current, child)
+		}
+		return current
+	}
 	if typed, isTyped := current.(And); isTyped {
 		return append(typed, next)
 	}
 	return And([]Selector{current, next})
 }
 
+// liftNode mirrors `lift` for the syntax tree, returning the node for the aggregate selector.
+func (p *Parser) liftNode(current, next *Node, selector Selector) *Node {
+	if !p.tree || current == nil {
+		return next
+	}
+	var children []*Node
+	if _, isAnd := current.Selector.(And); isAnd {
+		children = current.Children
+	} else {
+		children = []*Node{current}
+	}
+	if _, isAnd := next.Selector.(And); isAnd {
+		children = append(children, next.Children...)
+	} else {
+		children = append(children, next)
+	}
+	return &Node{Selector: selector, Span: Span{current.Span.Start, next.Span.End}, Children: children, source: p.s}
+}
+
+// addValue records the span of a value read for the current requirement.
+func (p *Parser) addValue(start, end int) {
+	if p.tree {
+		p.values = append(p.values, Span{start, end})
+	}
+}
+
 func (p *Parser) hasKey(key string) Selector {
 	return HasKey(key)
 }
@@ -150,29 +598,188 @@ func (p *Parser) notHasKey(key string) Selector {
 }
 
 func (p *Parser) equals(key string) (Selector, error) {
-	value := p.readWord()
-	return Equals{Key: key, Value: value}, nil
+	value, err := p.readValue()
+	if err != nil {
+		return nil, err
+	}
+	return Equals{Key: key, Value: value, rules: valueRulesFor(p.opts, value)}, nil
 }
 
 func (p *Parser) notEquals(key string) (Selector, error) {
-	value := p.readWord()
-	return NotEquals{Key: key, Value: value}, nil
+	value, err := p.readValue()
+	if err != nil {
+		return nil, err
+	}
+	return NotEquals{Key: key, Value: value, rules: valueRulesFor(p.opts, value)}, nil
+}
+
+func (p *Parser) compare(key, op string) (Selector, error) {
+	p.skipWhiteSpace()
+	start := p.pos
+	value, err := p.readValue()
+	if err != nil {
+		return nil, err
+	}
+	if err = checkNumber(value, p.opts.valueLimit()); err != nil {
+		return nil, p.invalidAt(err, start, p.s[start:p.pos])
+	}
+	switch op {
+	case OpGreaterThan:
+		return GreaterThan{Key: key, Value: value}, nil
+	case OpGreaterThanOrEqual:
+		return GreaterThanOrEqual{Key: key, Value: value}, nil
+	case OpLessThan:
+		return LessThan{Key: key, Value: value}, nil
+	default:
+		return LessThanOrEqual{Key: key, Value: value}, nil
+	}
+}
+
+func (p *Parser) like(key string) (Selector, error) {
+	p.skipWhiteSpace()
+	start := p.pos
+
+	// globs are read as written, like regular expressions, so `\*` reaches the glob as a literal star.
+	var patterns []string
+	if p.current() == OpenParens {
+		csv, err := p.readSet(true)
+		if err != nil {
+			return nil, err
+		}
+		patterns = csv
+	} else {
+		pattern, err := p.readPatternWord()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.checkValueAt(pattern, start); err != nil {
+			return nil, err
+		}
+		p.addValue(start, p.pos)
+		patterns = []string{pattern}
+	}
+
+	for _, pattern := range patterns {
+		if err := CheckGlob(pattern); err != nil {
+			return nil, p.invalidAt(err, start, p.s[start:p.pos])
+		}
+	}
+	return Like{Key: key, Patterns: patterns}, nil
+}
+
+func (p *Parser) matches(key string) (Selector, error) {
+	pattern, compiled, err := p.readPattern()
+	if err != nil {
+		return nil, err
+	}
+	return Matches{Key: key, Pattern: pattern, compiled: compiled}, nil
+}
+
+func (p *Parser) notMatches(key string) (Selector, error) {
+	pattern, compiled, err := p.readPattern()
+	if err != nil {
+		return nil, err
+	}
+	return NotMatches{Key: key, Pattern: pattern, compiled: compiled}, nil
+}
+
+// readPattern reads and compiles a regular expression.
+// Patterns are not subject to the value length limit.
+func (p *Parser) readPattern() (string, *regexp.Regexp, error) {
+	p.skipWhiteSpace()
+	start := p.pos
+	pattern, err := p.readPatternWord()
+	if err != nil {
+		return "", nil, err
+	}
+	compiled, err := compilePattern(pattern)
+	if err != nil {
+		return "", nil, p.invalidAt(err, start, p.s[start:p.pos])
+	}
+	p.addValue(start, p.pos)
+	return pattern, compiled, nil
 }
 
 func (p *Parser) in(key string) (Selector, error) {
-	csv, err := p.readCSV()
+	csv, err := p.readValueSet()
 	if err != nil {
 		return nil, err
 	}
-	return In{Key: key, Values: csv}, nil
+	return In{Key: key, Values: csv, RequireKey: p.opts.Strict(), rules: valueRulesFor(p.opts, csv...)}, nil
 }
 
 func (p *Parser) notIn(key string) (Selector, error) {
+	csv, err := p.readValueSet()
+	if err != nil {
+		return nil, err
+	}
+	return NotIn{Key: key, Values: csv, rules: valueRulesFor(p.opts, csv...)}, nil
+}
+
+// readValueSet reads the values for `in` or `notin`, which k8s-strict semantics require to be non-empty.
+func (p *Parser) readValueSet() ([]string, error) {
+	p.skipWhiteSpace()
+	start := p.pos
 	csv, err := p.readCSV()
 	if err != nil {
 		return nil, err
 	}
-	return NotIn{Key: key, Values: csv}, nil
+	if len(csv) == 0 && p.opts.Strict() {
+		return nil, newParseError(ErrEmptyValueSet, p.s, start, p.s[start:p.pos], TokenValue)
+	}
+	return csv, nil
+}
+
+// readKey reads a word and validates it as a key.
+func (p *Parser) readKey() (string, error) {
+	p.skipWhiteSpace()
+	start := p.pos
+	key := p.readWord()
+	maxTotalLen, maxLen := p.opts.keyLimits()
+	if err := checkKey(key, maxTotalLen, maxLen); err != nil {
+		return "", p.invalidAt(err, start, key)
+	}
+	p.key = Span{start, p.pos}
+	return key, nil
+}
+
+// readValue reads a word and validates it as a value.
+func (p *Parser) readValue() (string, error) {
+	p.skipWhiteSpace()
+	start := p.pos
+	if p.opts.Extended() {
+		value, err := p.readExtendedWord()
+		if err != nil {
+			return "", err
+		}
+		if err = p.checkValueAt(value, start); err != nil {
+			return "", err
+		}
+		p.addValue(start, p.pos)
+		return value, nil
+	}
+
+	value := p.readWord()
+	if err := p.checkValueAt(value, start); err != nil {
+		return "", err
+	}
+	p.addValue(start, p.pos)
+	return value, nil
+}
+
+// checkValueAt validates a value that was read starting at a given offset.
+// The extended dialect only checks the value length.
+func (p *Parser) checkValueAt(value string, start int) error {
+	if p.opts.Extended() {
+		if len(value) > p.opts.valueLimit() {
+			return newParseError(ErrValueTooLong, p.s, start, p.s[start:p.pos])
+		}
+		return nil
+	}
+	if err := checkValue(value, p.opts.valueLimit()); err != nil {
+		return p.invalidAt(err, start, value)
+	}
+	return nil
 }
 
 // done indicates the cursor is past the usable length of the string.
@@ -203,8 +810,11 @@ func (p *Parser) read() (r rune) {
 	return r
 }
 
-// current returns the rune at the current position.
+// current returns the rune at the current position, or 0 if the cursor is at the end.
 func (p *Parser) current() (r rune) {
+	if p.done() {
+		return
+	}
 	r, _ = utf8.DecodeRuneInString(p.s[p.pos:])
 	return
 }
@@ -227,11 +837,14 @@ func (p *Parser) prev() {
 // readOp reads a valid operator.
 // valid operators include:
 // [ =, ==, !=, in, notin ]
+// and in the extended dialect:
+// [ >, >=, <, <=, =~, !~, like, ~= ]
 // errors if it doesn't read one of the above, or there is another structural issue.
 func (p *Parser) readOp() (string, error) {
 	// skip preceding whitespace
 	p.skipWhiteSpace()
 
+	start := p.pos
 	var state int
 	var ch rune
 	var op []rune
@@ -256,56 +869,111 @@ func (p *Parser) readOp() (string, error) {
 				state = 7
 				break
 			}
-			return "", ErrInvalidOperator
+			if p.opts.Extended() && (ch == CloseAngle || ch == OpenAngle) {
+				state = 11
+				break
+			}
+			if p.opts.Extended() && ch == 'l' {
+				state = 12
+				break
+			}
+			if p.opts.Extended() && ch == Tilde {
+				state = 15
+				break
+			}
+			return "", p.errorAt(ErrInvalidOperator, start, p.operators()...)
 		case 1: // =
+			if p.opts.Extended() && ch == Tilde {
+				op = append(op, ch)
+				p.advance()
+				return string(op), nil
+			}
 			if p.isWhitespace(ch) || p.isAlpha(ch) || ch == Comma {
 				return string(op), nil
 			}
+			if p.opts.Extended() && !p.isSpecialSymbol(ch) {
+				return string(op), nil
+			}
 			if ch == Equal {
 				op = append(op, ch)
 				p.advance()
 				return string(op), nil
 			}
-			return "", ErrInvalidOperator
+			return "", p.errorAt(ErrInvalidOperator, start, OpEquals, OpDoubleEquals)
 		case 2: // !
-			if ch == Equal {
+			if ch == Equal || (p.opts.Extended() && ch == Tilde) {
 				op = append(op, ch)
 				p.advance()
 				return string(op), nil
 			}
-			return "", ErrInvalidOperator
+			if p.opts.Extended() {
+				return "", p.errorAt(ErrInvalidOperator, start, OpNotEquals, OpNotMatches)
+			}
+			return "", p.errorAt(ErrInvalidOperator, start, OpNotEquals)
 		case 6: // in
 			if ch == 'n' {
 				op = append(op, ch)
 				p.advance()
 				return string(op), nil
 			}
-			return "", ErrInvalidOperator
+			return "", p.errorAt(ErrInvalidOperator, start, OpIn)
 		case 7: // o
 			if ch == 'o' {
 				state = 8
 				break
 			}
-			return "", ErrInvalidOperator
+			return "", p.errorAt(ErrInvalidOperator, start, OpNotIn)
 		case 8: // t
 			if ch == 't' {
 				state = 9
 				break
 			}
-			return "", ErrInvalidOperator
+			return "", p.errorAt(ErrInvalidOperator, start, OpNotIn)
 		case 9: // i
 			if ch == 'i' {
 				state = 10
 				break
 			}
-			return "", ErrInvalidOperator
+			return "", p.errorAt(ErrInvalidOperator, start, OpNotIn)
 		case 10: // n
 			if ch == 'n' {
 				op = append(op, ch)
 				p.advance()
 				return string(op), nil
 			}
-			return "", ErrInvalidOperator
+			return "", p.errorAt(ErrInvalidOperator, start, OpNotIn)
+		case 11: // > or <, optionally followed by =
+			if ch == Equal {
+				op = append(op, ch)
+				p.advance()
+			}
+			return string(op), nil
+		case 12: // i
+			if ch == 'i' {
+				state = 13
+				break
+			}
+			return "", p.errorAt(ErrInvalidOperator, start, OpLike)
+		case 13: // k
+			if ch == 'k' {
+				state = 14
+				break
+			}
+			return "", p.errorAt(ErrInvalidOperator, start, OpLike)
+		case 14: // e
+			if ch == 'e' {
+				op = append(op, ch)
+				p.advance()
+				return string(op), nil
+			}
+			return "", p.errorAt(ErrInvalidOperator, start, OpLike)
+		case 15: // ~=
+			if ch == Equal {
+				op = append(op, ch)
+				p.advance()
+				return string(op), nil
+			}
+			return "", p.errorAt(ErrInvalidOperator, start, OpLikeSymbol)
 		}
 
 		op = append(op, ch)
@@ -325,7 +993,7 @@ func (p *Parser) readWord() string {
 
 	var word []rune
 	var ch rune
-	for {
+	for !p.done() {
 		ch = p.current()
 
 		if p.isWhitespace(ch) {
@@ -336,18 +1004,24 @@ func (p *Parser) readWord() string {
 		}
 		word = append(word, ch)
 		p.advance()
-
-		if p.done() {
-			return string(word)
-		}
 	}
+	return string(word)
 }
 
+// readCSV reads a parenthesized set of values.
 func (p *Parser) readCSV() (results []string, err error) {
+	return p.readSet(false)
+}
+
+// readSet reads a parenthesized set of values. If `patterns` is set, extended dialect values are read
+// as written, as `readPatternWord` does.
+func (p *Parser) readSet(patterns bool) (results []string, err error) {
 	// skip preceding whitespace
 	p.skipWhiteSpace()
 
 	var word []rune
+	var hasWord bool
+	var wordStart, wordEnd int
 	var ch rune
 	var state int
 
@@ -355,7 +1029,11 @@ func (p *Parser) readCSV() (results []string, err error) {
 		ch = p.current()
 
 		if p.done() {
-			err = ErrInvalidSelector
+			if state == 0 {
+				err = p.errorAt(ErrInvalidSelector, p.pos, string(OpenParens))
+				return
+			}
+			err = p.errorAt(ErrInvalidSelector, p.pos, string(Comma), string(CloseParens))
 			return
 		}
 
@@ -369,12 +1047,23 @@ func (p *Parser) readCSV() (results []string, err error) {
 				continue
 			}
 
+			err = p.errorAt(ErrInvalidSelector, p.pos, string(OpenParens))
+			return
+
 		case 1: // alphas (in word)
 
+			if ch == Comma || ch == CloseParens || p.isWhitespace(ch) {
+				wordEnd = p.pos
+			}
+
 			if ch == Comma {
-				if len(word) > 0 {
+				if hasWord {
+					if err = p.checkValueAt(string(word), wordStart); err != nil {
+						return
+					}
 					results = append(results, string(word))
-					word = nil
+					p.addValue(wordStart, wordEnd)
+					word, hasWord = nil, false
 				}
 				state = 2 // from comma
 				p.advance()
@@ -382,8 +1071,12 @@ func (p *Parser) readCSV() (results []string, err error) {
 			}
 
 			if ch == CloseParens {
-				if len(word) > 0 {
+				if hasWord {
+					if err = p.checkValueAt(string(word), wordStart); err != nil {
+						return
+					}
 					results = append(results, string(word))
+					p.addValue(wordStart, wordEnd)
 				}
 				p.advance()
 				return
@@ -395,8 +1088,20 @@ func (p *Parser) readCSV() (results []string, err error) {
 				continue
 			}
 
-			if !p.isAlpha(ch) {
-				err = ErrInvalidSelector
+			if p.opts.Extended() && ch == BackSlash {
+				if patterns {
+					word = append(word, p.readPatternEscape(0)...)
+					continue
+				}
+				if ch, err = p.readWordEscape(); err != nil {
+					return
+				}
+				word = append(word, ch)
+				continue
+			}
+
+			if !p.isValueRune(ch) {
+				err = p.errorAt(ErrInvalidSelector, p.pos, string(Comma), string(CloseParens))
 				return
 			}
 
@@ -421,19 +1126,42 @@ func (p *Parser) readCSV() (results []string, err error) {
 				continue
 			}
 
-			if p.isAlpha(ch) {
+			if p.opts.Extended() && p.isQuote(ch) {
+				wordStart = p.pos
+				var quoted string
+				if patterns {
+					quoted, err = p.readPatternQuoted()
+				} else {
+					quoted, err = p.readQuoted()
+				}
+				if err != nil {
+					return
+				}
+				word, hasWord = []rune(quoted), true
+				wordEnd = p.pos
+				state = 3
+				continue
+			}
+
+			if p.isValueRune(ch) {
+				wordStart = p.pos
+				hasWord = true
 				state = 1
 				continue
 			}
 
-			err = ErrInvalidSelector
+			err = p.errorAt(ErrInvalidSelector, p.pos, TokenValue, string(Comma), string(CloseParens))
 			return
 
 		case 3: //whitespace after alpha
 
 			if ch == CloseParens {
-				if len(word) > 0 {
+				if hasWord {
+					if err = p.checkValueAt(string(word), wordStart); err != nil {
+						return
+					}
 					results = append(results, string(word))
+					p.addValue(wordStart, wordEnd)
 				}
 				p.advance()
 				return
@@ -445,22 +1173,168 @@ func (p *Parser) readCSV() (results []string, err error) {
 			}
 
 			if ch == Comma {
-				if len(word) > 0 {
+				if hasWord {
+					if err = p.checkValueAt(string(word), wordStart); err != nil {
+						return
+					}
 					results = append(results, string(word))
-					word = nil
+					p.addValue(wordStart, wordEnd)
+					word, hasWord = nil, false
 				}
 				p.advance()
 				state = 2
 				continue
 			}
 
-			err = ErrInvalidSelector
+			err = p.errorAt(ErrInvalidSelector, p.pos, string(Comma), string(CloseParens))
 			return
 
 		}
 	}
 }
 
+// readQuoted reads a single or double quoted value, leaving the cursor after the closing quote.
+// Within the quotes a backslash escapes the following rune.
+func (p *Parser) readQuoted() (string, error) {
+	start := p.pos
+	quote := p.read()
+
+	var word []rune
+	var ch rune
+	var err error
+	for !p.done() {
+		ch = p.current()
+		if ch == quote {
+			p.advance()
+			return string(word), nil
+		}
+		if ch == BackSlash {
+			if ch, err = p.readEscape(); err != nil {
+				return "", err
+			}
+			word = append(word, ch)
+			continue
+		}
+		word = append(word, ch)
+		p.advance()
+	}
+	return "", newParseError(ErrInvalidSelector, p.s, start, p.s[start:], string(quote))
+}
+
+// readEscape reads a backslash and the rune it escapes, returning the escaped rune.
+func (p *Parser) readEscape() (rune, error) {
+	start := p.pos
+	p.advance() // skip the backslash
+	if p.done() {
+		return 0, newParseError(ErrInvalidSelector, p.s, start, string(BackSlash), TokenValue)
+	}
+	return p.read(), nil
+}
+
+// readWordEscape reads a backslash in an unquoted word. It escapes whitespace, syntax, quotes and backslashes;
+// before any other rune it is a literal backslash, as in the default dialect, so `a\b` reads the same in both.
+func (p *Parser) readWordEscape() (rune, error) {
+	if p.pos+1 < len(p.s) {
+		next, _ := utf8.DecodeRuneInString(p.s[p.pos+1:])
+		if !p.isWhitespace(next) && !p.isSpecialSymbol(next) && !p.isQuote(next) && next != BackSlash {
+			p.advance()
+			return BackSlash, nil
+		}
+	}
+	return p.readEscape()
+}
+
+// readExtendedWord reads a value in the extended dialect; either a quoted value
+// or a word that may contain backslash escapes, read with `readWordEscape`.
+func (p *Parser) readExtendedWord() (string, error) {
+	p.skipWhiteSpace()
+	if p.isQuote(p.current()) {
+		return p.readQuoted()
+	}
+
+	var word []rune
+	var ch rune
+	var err error
+	for !p.done() {
+		ch = p.current()
+		if p.isWhitespace(ch) || p.isSpecialSymbol(ch) {
+			break
+		}
+		if ch == BackSlash {
+			if ch, err = p.readWordEscape(); err != nil {
+				return "", err
+			}
+			word = append(word, ch)
+			continue
+		}
+		word = append(word, ch)
+		p.advance()
+	}
+	return string(word), nil
+}
+
+// readPatternWord reads a pattern; either a quoted pattern or a word. Patterns are read as written, so
+// backslashes reach the pattern syntax, e.g. `a\.b` is the pattern `a\.b`; only an escaped closing quote is unescaped.
+func (p *Parser) readPatternWord() (string, error) {
+	p.skipWhiteSpace()
+	if p.isQuote(p.current()) {
+		return p.readPatternQuoted()
+	}
+
+	var word []rune
+	var ch rune
+	for !p.done() {
+		ch = p.current()
+		if p.isWhitespace(ch) || p.isSpecialSymbol(ch) {
+			break
+		}
+		if ch == BackSlash {
+			word = append(word, p.readPatternEscape(0)...)
+			continue
+		}
+		word = append(word, ch)
+		p.advance()
+	}
+	return string(word), nil
+}
+
+// readPatternQuoted reads a single or double quoted pattern, leaving the cursor after the closing quote.
+func (p *Parser) readPatternQuoted() (string, error) {
+	start := p.pos
+	quote := p.read()
+
+	var word []rune
+	var ch rune
+	for !p.done() {
+		ch = p.current()
+		if ch == quote {
+			p.advance()
+			return string(word), nil
+		}
+		if ch == BackSlash {
+			word = append(word, p.readPatternEscape(quote)...)
+			continue
+		}
+		word = append(word, ch)
+		p.advance()
+	}
+	return "", newParseError(ErrInvalidSelector, p.s, start, p.s[start:], string(quote))
+}
+
+// readPatternEscape reads a backslash and the rune after it as written,
+// except that an escaped closing quote is read as the quote alone.
+func (p *Parser) readPatternEscape(quote rune) []rune {
+	p.advance() // skip the backslash
+	if p.done() {
+		return []rune{BackSlash}
+	}
+	ch := p.read()
+	if quote != 0 && ch == quote {
+		return []rune{ch}
+	}
+	return []rune{BackSlash, ch}
+}
+
 func (p *Parser) skipWhiteSpace() {
 	if p.done() {
 		return
@@ -497,13 +1371,36 @@ func (p *Parser) skipToComma() (ch rune) {
 	}
 }
 
-// isWhitespace returns true if the rune is a space, tab, or newline.
+// errorAt returns a parse error for the text between start and the current rune.
+func (p *Parser) errorAt(err error, start int, expected ...string) *ParseError {
+	end := p.pos
+	if ch := p.current(); !p.isTerminator(ch) && !p.isWhitespace(ch) {
+		_, width := utf8.DecodeRuneInString(p.s[p.pos:])
+		end += width
+	}
+	return newParseError(err, p.s, start, p.s[start:end], expected...)
+}
+
+// invalidAt returns a parse error for a key or value that failed validation.
+// If nothing was read, e.g. for an empty key, the rune at the start is reported as found instead.
+func (p *Parser) invalidAt(err error, start int, text string) *ParseError {
+	if len(text) == 0 && start < len(p.s) {
+		_, width := utf8.DecodeRuneInString(p.s[start:])
+		text = p.s[start : start+width]
+	}
+	return newParseError(err, p.s, start, text)
+}
+
+// isWhitespace returns true if the rune is whitespace under the parser's whitespace rules.
 func (p *Parser) isWhitespace(ch rune) bool {
-	return ch == Space || ch == Tab || ch == CarriageReturn || ch == NewLine
+	return p.opts.Whitespace.Is(ch)
 }
 
 // isSpecialSymbol returns if the ch is on the selector symbol list.
 func (p *Parser) isSpecialSymbol(ch rune) bool {
+	if p.opts.Extended() && (ch == Pipe || ch == OpenAngle || ch == CloseAngle) {
+		return true
+	}
 	return isSelectorSymbol(ch)
 }
 
@@ -515,3 +1412,25 @@ func (p *Parser) isTerminator(ch rune) bool {
 func (p *Parser) isAlpha(ch rune) bool {
 	return isAlpha(ch)
 }
+
+// isValueRune returns if the rune can appear in an unquoted value in a set; the default dialect only allows letters and digits.
+// The extended dialect allows anything that isn't whitespace or syntax, e.g. glob patterns.
+func (p *Parser) isValueRune(ch rune) bool {
+	if p.opts.Extended() {
+		return !p.isWhitespace(ch) && !p.isSpecialSymbol(ch) && !p.isQuote(ch)
+	}
+	return p.isAlpha(ch)
+}
+
+// isGroupEnd returns if the cursor is on a token that ends a requirement in the extended grammar.
+func (p *Parser) isGroupEnd(ch rune) bool {
+	if !p.opts.Extended() {
+		return false
+	}
+	return ch == CloseParens || p.isOrNext()
+}
+
+// isQuote returns if the rune opens a quoted value.
+func (p *Parser) isQuote(ch rune) bool {
+	return ch == DoubleQuote || ch == SingleQuote
+}
@@ -0,0 +1,117 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenameKeys returns a copy of a selector with every key mapped by a function.
+// Each renamed key is checked with `CheckKey`; if any are invalid the selector is not returned,
+// and the error is a `KeyErrors` with one `*KeyError` per invalid key.
+// Selector types from other packages return `ErrUnsupportedSelector`, as their keys can't be renamed.
+func RenameKeys(s Selector, rename func(key string) string) (Selector, error) {
+	var errs KeyErrors
+	var unsupported error
+	checked := map[string]bool{}
+	renameKey := func(key string) string {
+		renamed := rename(key)
+		if !checked[key] {
+			checked[key] = true
+			if err := CheckKey(renamed); err != nil {
+				errs = append(errs, &KeyError{Key: key, Renamed: renamed, Err: err})
+			}
+		}
+		return renamed
+	}
+
+	output := Rewrite(s, func(s Selector) Selector {
+		renamed, ok := renameKeys(s, renameKey)
+		if !ok && unsupported == nil {
+			unsupported = fmt.Errorf("%w: %T", ErrUnsupportedSelector, s)
+		}
+		return renamed
+	})
+	if unsupported != nil {
+		return nil, unsupported
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return output, nil
+}
+
+// AddPrefix returns a copy of a selector with a dns prefix added to every key, e.g. `app` becomes `example.com/app`.
+// Keys that already have a prefix are invalid once prefixed, so are reported as errors.
+func AddPrefix(s Selector, prefix string) (Selector, error) {
+	return RenameKeys(s, func(key string) string {
+		return prefix + string(ForwardSlash) + key
+	})
+}
+
+// StripPrefix returns a copy of a selector with a dns prefix removed from the keys that have it,
+// e.g. `example.com/app` becomes `app`. Other keys are unchanged.
+func StripPrefix(s Selector, prefix string) (Selector, error) {
+	return RenameKeys(s, func(key string) string {
+		return strings.TrimPrefix(key, prefix+string(ForwardSlash))
+	})
+}
+
+// MapKeys returns a copy of a selector with keys renamed by an alias table; keys not in the table are unchanged.
+func MapKeys(s Selector, aliases map[string]string) (Selector, error) {
+	return RenameKeys(s, func(key string) string {
+		if alias, hasAlias := aliases[key]; hasAlias {
+			return alias
+		}
+		return key
+	})
+}
+
+// renameKeys renames the key of a single selector, returning false for selector types it doesn't know.
+// Combinations are returned as is, as `Rewrite` has already renamed their children.
+func renameKeys(s Selector, rename func(string) string) (Selector, bool) {
+	switch typed := s.(type) {
+	case And, Or, Not, Fold:
+		return s, true
+	case HasKey:
+		return HasKey(rename(string(typed))), true
+	case NotHasKey:
+		return NotHasKey(rename(string(typed))), true
+	case Equals:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	case NotEquals:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	case In:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	case NotIn:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	case GreaterThan:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	case GreaterThanOrEqual:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	case LessThan:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	case LessThanOrEqual:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	case Like:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	case Matches:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	case NotMatches:
+		typed.Key = rename(typed.Key)
+		return typed, true
+	}
+	if _, isParent := s.(Parent); isParent {
+		return s, true
+	}
+	return s, false
+}
@@ -0,0 +1,86 @@
+package selector
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+// customLeaf is a selector type from outside the package.
+type customLeaf struct{}
+
+func (customLeaf) Matches(Labels) bool { return true }
+func (customLeaf) Validate() error     { return nil }
+func (customLeaf) String() string      { return "custom" }
+
+func TestAddPrefix(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseWithOptions("app == web, tier in (a, b), !old || cpu > 4, name =~ web-.*", Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	prefixed, err := AddPrefix(selector, "example.com")
+	assert.Nil(err)
+	assert.Equal("example.com/app == web, example.com/tier in (a, b), !example.com/old || example.com/cpu > 4, example.com/name =~ web-.*", prefixed.String())
+	assert.True(prefixed.Matches(Labels{"example.com/cpu": "5", "example.com/name": "web-1"}))
+	assert.False(prefixed.Matches(Labels{"cpu": "5", "name": "web-1"}))
+	assert.Nil(prefixed.Validate())
+
+	stripped, err := StripPrefix(prefixed, "example.com")
+	assert.Nil(err)
+	assert.Equal(selector.String(), stripped.String())
+}
+
+func TestAddPrefixErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := And{Equals{Key: "internal.io/secret", Value: "a"}, HasKey("app"), NotHasKey("internal.io/secret")}
+	_, err := AddPrefix(selector, "example.com")
+	assert.NotNil(err)
+	var keyErrors KeyErrors
+	assert.True(errors.As(err, &keyErrors))
+	assert.Len(keyErrors, 1)
+	assert.Equal("internal.io/secret", keyErrors[0].Key)
+	assert.Equal("example.com/internal.io/secret", keyErrors[0].Renamed)
+	assert.True(errors.Is(err, ErrKeyInvalidCharacter))
+
+	_, err = AddPrefix(HasKey("app"), strings.Repeat("a", MaxDNSPrefixLen+1))
+	assert.True(errors.Is(err, ErrKeyDNSPrefixTooLong))
+}
+
+func TestMapKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := Parse("app == web, env notin (prod), x")
+	assert.Nil(err)
+	mapped, err := MapKeys(selector, map[string]string{"app": "app.kubernetes.io/name", "env": "example.com/environment"})
+	assert.Nil(err)
+	assert.Equal("app.kubernetes.io/name == web, example.com/environment notin (prod), x", mapped.String())
+	assert.Equal("app == web, env notin (prod), x", selector.String())
+
+	_, err = MapKeys(selector, map[string]string{"app": "", "env": "-bad", "x": "-bad"})
+	var keyErrors KeyErrors
+	assert.True(errors.As(err, &keyErrors))
+	assert.Len(keyErrors, 3)
+	assert.True(errors.Is(keyErrors[0], ErrKeyEmpty))
+	assert.Equal(`key "app" renamed to "": key empty`, keyErrors[0].Error())
+	assert.Equal("env", keyErrors[1].Key)
+	assert.Equal("x", keyErrors[2].Key)
+}
+
+func TestRenameKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := Fold{Selector: Not{Selector: In{Key: "app", Values: []string{"web"}, RequireKey: true}}}
+	renamed, err := RenameKeys(selector, strings.ToUpper)
+	assert.Nil(err)
+	assert.Equal(Fold{Selector: Not{Selector: In{Key: "APP", Values: []string{"web"}, RequireKey: true}}}, renamed)
+
+	_, err = RenameKeys(And{HasKey("x"), customLeaf{}}, strings.ToUpper)
+	assert.True(errors.Is(err, ErrUnsupportedSelector))
+
+	renamed, err = RenameKeys(anyOf{selectors: []Selector{HasKey("x")}}, strings.ToUpper)
+	assert.Nil(err)
+	assert.Equal(anyOf{selectors: []Selector{HasKey("X")}}, renamed)
+}
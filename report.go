@@ -0,0 +1,84 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Report explains how a selector evaluated a label set, as returned by `Explain`.
+// It renders as text with `String()`, and as JSON with `encoding/json`.
+type Report struct {
+	Selector     string    `json:"selector"`
+	Matches      bool      `json:"matches"`
+	Requirements []Outcome `json:"requirements"`
+}
+
+// Outcome is the result of one requirement, or combination of requirements, in a `Report`.
+// Requirements list the label they saw: `Present` is false if the key was absent.
+// Combinations are followed by their children, one level deeper.
+type Outcome struct {
+	Requirement  string `json:"requirement"`
+	Depth        int    `json:"depth"`
+	Key          string `json:"key,omitempty"`
+	Value        string `json:"value,omitempty"`
+	Present      bool   `json:"present,omitempty"`
+	Matches      bool   `json:"matches"`
+	FirstFailure bool   `json:"firstFailure,omitempty"`
+}
+
+// FirstFailure returns the first requirement that failed, which is why the selector didn't match.
+// Within a disjunction or negation, the combination as a whole is the failure.
+func (r Report) FirstFailure() (Outcome, bool) {
+	for _, outcome := range r.Requirements {
+		if outcome.FirstFailure {
+			return outcome, true
+		}
+	}
+	return Outcome{}, false
+}
+
+// String returns the report as text, one line per outcome, e.g.
+//
+//	app in (web), env != prod: doesn't match
+//	  fail app in (web) (app="api") <- first failure
+//	  pass env != prod (env absent)
+func (r Report) String() string {
+	var output strings.Builder
+	selector := r.Selector
+	if selector == "" {
+		selector = "(empty selector)"
+	}
+	if r.Matches {
+		fmt.Fprintf(&output, "%s: matches\n", selector)
+	} else {
+		fmt.Fprintf(&output, "%s: doesn't match\n", selector)
+	}
+	for _, outcome := range r.Requirements {
+		output.WriteString(strings.Repeat("  ", outcome.Depth+1))
+		output.WriteString(outcome.String())
+		output.WriteRune(NewLine)
+	}
+	return output.String()
+}
+
+// String returns the outcome as a line of text, e.g. `fail app in (web) (app="api")`.
+func (o Outcome) String() string {
+	var output strings.Builder
+	if o.Matches {
+		output.WriteString("pass ")
+	} else {
+		output.WriteString("fail ")
+	}
+	output.WriteString(o.Requirement)
+	if o.Key != "" {
+		if o.Present {
+			fmt.Fprintf(&output, " (%s=%q)", o.Key, o.Value)
+		} else {
+			fmt.Fprintf(&output, " (%s absent)", o.Key)
+		}
+	}
+	if o.FirstFailure {
+		output.WriteString(" <- first failure")
+	}
+	return output.String()
+}
@@ -0,0 +1,37 @@
+package selector
+
+import (
+	"encoding/json"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestReportString(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, _ := Parse("app in (web), env != prod")
+	report := Explain(selector, Labels{"app": "api"})
+	assert.Equal(`app in (web), env != prod: doesn't match
+  fail app in (web) (app="api") <- first failure
+  pass env != prod (env absent)
+`, report.String())
+
+	report = Explain(And{}, Labels{})
+	assert.Equal("(empty selector): matches\n", report.String())
+}
+
+func TestReportJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, _ := Parse("app in (web), env != prod")
+	contents, err := json.Marshal(Explain(selector, Labels{"app": "api"}))
+	assert.Nil(err)
+	assert.Equal(`{"selector":"app in (web), env != prod","matches":false,"requirements":[`+
+		`{"requirement":"app in (web)","depth":0,"key":"app","value":"api","present":true,"matches":false,"firstFailure":true},`+
+		`{"requirement":"env != prod","depth":0,"key":"env","matches":true}]}`, string(contents))
+
+	var decoded Report
+	assert.Nil(json.Unmarshal(contents, &decoded))
+	assert.Equal(Explain(selector, Labels{"app": "api"}), decoded)
+}
@@ -0,0 +1,199 @@
+package selector
+
+import "fmt"
+
+// Requirement is a uniform description of a single requirement, similar to a kubernetes `labels.Requirement`.
+// `Operator` is one of the `Op` constants; `OpExists` and `OpDoesNotExist` describe `key` and `!key`,
+// which have no values. `OpEquals`, `OpNotEquals`, the numeric comparisons, `OpMatches` and `OpNotMatches`
+// have a single value, and `OpIn`, `OpNotIn` and `OpLike` have a value per member of their set.
+// `RequireKey` is only used by `OpIn`, and is set if the key must be present, as `In.RequireKey` is.
+type Requirement struct {
+	Key        string
+	Operator   string
+	Values     []string
+	RequireKey bool
+}
+
+// String returns the requirement in selector syntax.
+func (r Requirement) String() string {
+	if s, err := r.selector(Options{Dialect: DialectExtended}); err == nil {
+		return s.String()
+	}
+	return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, quoteSetValues(r.Values))
+}
+
+// Requirements decomposes a selector into its requirements, returning false if the selector is not
+// a conjunction of requirements, i.e. it contains an `Or`, a `Not` or a selector type from another package.
+// The values are copies, so changing a requirement doesn't change the selector.
+func Requirements(s Selector) ([]Requirement, bool) {
+	switch typed := s.(type) {
+	case And:
+		requirements := []Requirement{}
+		for _, child := range typed {
+			childRequirements, ok := Requirements(child)
+			if !ok {
+				return nil, false
+			}
+			requirements = append(requirements, childRequirements...)
+		}
+		return requirements, true
+	case HasKey:
+		return []Requirement{{Key: string(typed), Operator: OpExists}}, true
+	case NotHasKey:
+		return []Requirement{{Key: string(typed), Operator: OpDoesNotExist}}, true
+	case Equals:
+		return []Requirement{{Key: typed.Key, Operator: OpEquals, Values: []string{typed.Value}}}, true
+	case NotEquals:
+		return []Requirement{{Key: typed.Key, Operator: OpNotEquals, Values: []string{typed.Value}}}, true
+	case In:
+		return []Requirement{{Key: typed.Key, Operator: OpIn, Values: copyValues(typed.Values), RequireKey: typed.RequireKey}}, true
+	case NotIn:
+		return []Requirement{{Key: typed.Key, Operator: OpNotIn, Values: copyValues(typed.Values)}}, true
+	case GreaterThan:
+		return []Requirement{{Key: typed.Key, Operator: OpGreaterThan, Values: []string{typed.Value}}}, true
+	case GreaterThanOrEqual:
+		return []Requirement{{Key: typed.Key, Operator: OpGreaterThanOrEqual, Values: []string{typed.Value}}}, true
+	case LessThan:
+		return []Requirement{{Key: typed.Key, Operator: OpLessThan, Values: []string{typed.Value}}}, true
+	case LessThanOrEqual:
+		return []Requirement{{Key: typed.Key, Operator: OpLessThanOrEqual, Values: []string{typed.Value}}}, true
+	case Like:
+		return []Requirement{{Key: typed.Key, Operator: OpLike, Values: copyValues(typed.Patterns)}}, true
+	case Matches:
+		return []Requirement{{Key: typed.Key, Operator: OpMatches, Values: []string{typed.Pattern}}}, true
+	case NotMatches:
+		return []Requirement{{Key: typed.Key, Operator: OpNotMatches, Values: []string{typed.Pattern}}}, true
+	}
+	return nil, false
+}
+
+// FromRequirements returns a selector for a conjunction of requirements, validating each one
+// as the parser would with the given options; e.g. the extended operators need `DialectExtended`,
+// and `In` requires its key if the requirement does or with k8s-strict semantics.
+// The selectors have copies of the values, so changing a requirement afterwards doesn't change them.
+// A single requirement is returned as is, otherwise the requirements are combined in an `And`.
+func FromRequirements(requirements []Requirement, opts Options) (Selector, error) {
+	selectors := make(And, 0, len(requirements))
+	for index, requirement := range requirements {
+		s, err := requirement.selector(opts)
+		if err != nil {
+			return nil, fmt.Errorf("requirement %d (%s): %w", index, requirement.Key, err)
+		}
+		selectors = append(selectors, s)
+	}
+	if len(selectors) == 1 {
+		return selectors[0], nil
+	}
+	return selectors, nil
+}
+
+// selector returns the selector for the requirement, validated with the given options.
+func (r Requirement) selector(opts Options) (Selector, error) {
+	maxTotalLen, maxLen := opts.keyLimits()
+	if err := checkKey(r.Key, maxTotalLen, maxLen); err != nil {
+		return nil, err
+	}
+	if r.Operator != OpExists && r.Operator != OpDoesNotExist && !r.allowed(opts) {
+		return nil, ErrInvalidOperator
+	}
+
+	switch r.Operator {
+	case OpExists, OpDoesNotExist:
+		if len(r.Values) != 0 {
+			return nil, ErrValueCount
+		}
+		if r.Operator == OpExists {
+			return HasKey(r.Key), nil
+		}
+		return NotHasKey(r.Key), nil
+	case OpIn, OpNotIn:
+		if len(r.Values) == 0 && opts.Strict() {
+			return nil, ErrEmptyValueSet
+		}
+		if err := r.checkValues(opts); err != nil {
+			return nil, err
+		}
+		if r.Operator == OpIn {
+			return In{Key: r.Key, Values: copyValues(r.Values), RequireKey: r.RequireKey || opts.Strict(), rules: valueRulesFor(opts, r.Values...)}, nil
+		}
+		return NotIn{Key: r.Key, Values: copyValues(r.Values), rules: valueRulesFor(opts, r.Values...)}, nil
+	case OpLike, OpLikeSymbol:
+		if len(r.Values) == 0 {
+			return nil, ErrValueCount
+		}
+		for _, pattern := range r.Values {
+			if err := CheckGlob(pattern); err != nil {
+				return nil, err
+			}
+		}
+		return Like{Key: r.Key, Patterns: copyValues(r.Values)}, nil
+	}
+
+	if len(r.Values) != 1 {
+		return nil, ErrValueCount
+	}
+	value := r.Values[0]
+	switch r.Operator {
+	case OpMatches:
+		return NewMatches(r.Key, value)
+	case OpNotMatches:
+		return NewNotMatches(r.Key, value)
+	case OpGreaterThan, OpGreaterThanOrEqual, OpLessThan, OpLessThanOrEqual:
+		if err := checkNumber(value, opts.valueLimit()); err != nil {
+			return nil, err
+		}
+	default:
+		if err := r.checkValues(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	switch r.Operator {
+	case OpEquals, OpDoubleEquals:
+		return Equals{Key: r.Key, Value: value, rules: valueRulesFor(opts, value)}, nil
+	case OpNotEquals:
+		return NotEquals{Key: r.Key, Value: value, rules: valueRulesFor(opts, value)}, nil
+	case OpGreaterThan:
+		return GreaterThan{Key: r.Key, Value: value}, nil
+	case OpGreaterThanOrEqual:
+		return GreaterThanOrEqual{Key: r.Key, Value: value}, nil
+	case OpLessThan:
+		return LessThan{Key: r.Key, Value: value}, nil
+	default:
+		return LessThanOrEqual{Key: r.Key, Value: value}, nil
+	}
+}
+
+// allowed returns if the requirement's operator is valid in the options' dialect and allowed by the options.
+func (r Requirement) allowed(opts Options) bool {
+	for _, op := range NewParser("", opts).operators() {
+		if op == r.Operator {
+			return true
+		}
+	}
+	return false
+}
+
+// checkValues validates the values as the parser would; the extended dialect only checks their length.
+func (r Requirement) checkValues(opts Options) error {
+	for _, value := range r.Values {
+		if opts.Extended() {
+			if len(value) > opts.valueLimit() {
+				return ErrValueTooLong
+			}
+			continue
+		}
+		if err := checkValue(value, opts.valueLimit()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyValues returns a copy of a list of values, which is nil only if the list is.
+func copyValues(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string{}, values...)
+}
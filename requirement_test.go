@@ -0,0 +1,143 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestRequirements(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := Parse("x == a, !y, z in (b, c), w, v notin (d), u != e")
+	assert.Nil(err)
+	requirements, ok := Requirements(selector)
+	assert.True(ok)
+	assert.Equal([]Requirement{
+		{Key: "x", Operator: OpEquals, Values: []string{"a"}},
+		{Key: "y", Operator: OpDoesNotExist},
+		{Key: "z", Operator: OpIn, Values: []string{"b", "c"}},
+		{Key: "w", Operator: OpExists},
+		{Key: "v", Operator: OpNotIn, Values: []string{"d"}},
+		{Key: "u", Operator: OpNotEquals, Values: []string{"e"}},
+	}, requirements)
+
+	roundTrip, err := FromRequirements(requirements, Options{})
+	assert.Nil(err)
+	assert.Equal(selector, roundTrip)
+}
+
+func TestRequirementsExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := Options{Dialect: DialectExtended}
+	selector, err := ParseWithOptions("x > 4, y <= 2.5, z like (a*, b?), w =~ web-.*, v !~ api, u >= 1, t < 0", opts)
+	assert.Nil(err)
+	requirements, ok := Requirements(selector)
+	assert.True(ok)
+	assert.Len(requirements, 7)
+	assert.Equal(Requirement{Key: "z", Operator: OpLike, Values: []string{"a*", "b?"}}, requirements[2])
+	assert.Equal(Requirement{Key: "w", Operator: OpMatches, Values: []string{"web-.*"}}, requirements[3])
+
+	roundTrip, err := FromRequirements(requirements, opts)
+	assert.Nil(err)
+	assert.Equal(selector.String(), roundTrip.String())
+	assert.True(roundTrip.Matches(Labels{"x": "5", "y": "1", "z": "ax", "w": "web-1", "v": "web", "u": "1", "t": "-1"}))
+
+	_, err = FromRequirements(requirements, Options{})
+	assert.True(errors.Is(err, ErrInvalidOperator))
+}
+
+func TestRequirementsNotDecomposable(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok := Requirements(Or{HasKey("x"), HasKey("y")})
+	assert.False(ok)
+	_, ok = Requirements(And{HasKey("x"), Not{Selector: And{HasKey("y")}}})
+	assert.False(ok)
+
+	requirements, ok := Requirements(And{})
+	assert.True(ok)
+	assert.Empty(requirements)
+	requirements, ok = Requirements(HasKey("x"))
+	assert.True(ok)
+	assert.Equal([]Requirement{{Key: "x", Operator: OpExists}}, requirements)
+}
+
+func TestFromRequirements(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := FromRequirements([]Requirement{{Key: "x", Operator: OpIn, Values: []string{"a"}}}, Options{Semantics: SemanticsK8sStrict})
+	assert.Nil(err)
+	assert.Equal(In{Key: "x", Values: []string{"a"}, RequireKey: true}, selector)
+
+	selector, err = FromRequirements(nil, Options{})
+	assert.Nil(err)
+	assert.True(selector.Matches(Labels{}))
+
+	_, err = FromRequirements([]Requirement{{Key: "x", Operator: OpEquals}}, Options{})
+	assert.True(errors.Is(err, ErrValueCount))
+	_, err = FromRequirements([]Requirement{{Key: "x", Operator: OpExists, Values: []string{"a"}}}, Options{})
+	assert.True(errors.Is(err, ErrValueCount))
+	_, err = FromRequirements([]Requirement{{Key: "x", Operator: OpIn}}, Options{Semantics: SemanticsK8sStrict})
+	assert.True(errors.Is(err, ErrEmptyValueSet))
+	_, err = FromRequirements([]Requirement{{Key: "x", Operator: "~"}}, Options{})
+	assert.True(errors.Is(err, ErrInvalidOperator))
+	_, err = FromRequirements([]Requirement{{Key: "x", Operator: OpNotIn, Values: []string{"a"}}}, Options{Operators: []string{OpIn}})
+	assert.True(errors.Is(err, ErrInvalidOperator))
+
+	_, err = FromRequirements([]Requirement{{Key: "x", Operator: OpExists}, {Key: "-y", Operator: OpExists}}, Options{})
+	assert.NotNil(err)
+	assert.Equal("requirement 1 (-y): "+ErrKeyInvalidCharacter.Error(), err.Error())
+
+	_, err = FromRequirements([]Requirement{{Key: "x", Operator: OpGreaterThan, Values: []string{"a"}}}, Options{Dialect: DialectExtended})
+	assert.True(errors.Is(err, ErrValueNotNumeric))
+	_, err = FromRequirements([]Requirement{{Key: "x", Operator: OpMatches, Values: []string{"("}}}, Options{Dialect: DialectExtended})
+	assert.True(errors.Is(err, ErrInvalidPattern))
+}
+
+func TestRequirementString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("x in (a, b)", Requirement{Key: "x", Operator: OpIn, Values: []string{"a", "b"}}.String())
+	assert.Equal("!x", Requirement{Key: "x", Operator: OpDoesNotExist}.String())
+	assert.Equal("x == a", Requirement{Key: "x", Operator: OpEquals, Values: []string{"a"}}.String())
+}
+
+func TestRequirementsCopyValues(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := And{In{Key: "x", Values: []string{"a", "b"}}, NotIn{Key: "y", Values: []string{"c"}}, Like{Key: "z", Patterns: []string{"d*"}}}
+	requirements, ok := Requirements(selector)
+	assert.True(ok)
+	for _, requirement := range requirements {
+		requirement.Values[0] = "changed"
+	}
+	assert.Equal([]string{"a", "b"}, selector[0].(In).Values)
+	assert.Equal([]string{"c"}, selector[1].(NotIn).Values)
+	assert.Equal([]string{"d*"}, selector[2].(Like).Patterns)
+
+	values := []string{"a"}
+	built, err := FromRequirements([]Requirement{{Key: "x", Operator: OpIn, Values: values}}, Options{})
+	assert.Nil(err)
+	values[0] = "changed"
+	assert.Equal([]string{"a"}, built.(In).Values)
+}
+
+func TestRequirementsRequireKey(t *testing.T) {
+	assert := assert.New(t)
+
+	strict, err := ParseWithOptions("x in (a)", Options{Semantics: SemanticsK8sStrict})
+	assert.Nil(err)
+	requirements, ok := Requirements(strict)
+	assert.True(ok)
+	assert.Equal([]Requirement{{Key: "x", Operator: OpIn, Values: []string{"a"}, RequireKey: true}}, requirements)
+
+	rebuilt, err := FromRequirements(requirements, Options{})
+	assert.Nil(err)
+	assert.Equal(strict, rebuilt)
+
+	requirements, _ = Requirements(In{Key: "x", Values: []string{"a"}})
+	assert.False(requirements[0].RequireKey)
+}
@@ -0,0 +1,16 @@
+package selector
+
+// Satisfiable returns if any label set can match a selector, and if not, a contradiction
+// naming a minimal set of conflicting requirements, e.g. `x == a` and `x == b`, or `x` and `!x`.
+//
+// The answer is exact for the package's requirement types, including the key-absent behavior of `In`
+// and numeric comparisons, and for `And`, `Or` and `Not` combinations of them. Selectors it can't reason
+// about exactly, i.e. types from other packages, case folding, or some combinations of glob and regular
+// expression patterns, are assumed to be satisfiable.
+func Satisfiable(s Selector) (bool, *Contradiction) {
+	result, _, contradiction, _ := solve(s)
+	if result == unsatisfiable {
+		return false, contradiction
+	}
+	return true, nil
+}
@@ -0,0 +1,149 @@
+package selector
+
+import (
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestSatisfiableContradictions(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		query        string
+		requirements []string
+	}{
+		{"x=a,x=b", []string{"x == a", "x == b"}},
+		{"x,!x", []string{"x", "!x"}},
+		{"x=a,!x", []string{"x == a", "!x"}},
+		{"y, x=a, z != c, x in (b, c)", []string{"x == a", "x in (b, c)"}},
+		{"x in (a),x notin (a)", []string{"x in (a)", "x notin (a)"}},
+		{"x in (a, b), x in (c)", []string{"x in (a, b)", "x in (c)"}},
+		{"x != a, x == a, y", []string{"x != a", "x == a"}},
+	}
+	for _, c := range cases {
+		selector, err := ParseWithOptions(c.query, Options{Semantics: SemanticsK8sStrict})
+		assert.Nil(err, c.query)
+		ok, contradiction := Satisfiable(selector)
+		assert.False(ok, c.query)
+		assert.NotNil(contradiction, c.query)
+		assert.Equal("x", contradiction.Key, c.query)
+		var requirements []string
+		for _, requirement := range contradiction.Requirements {
+			requirements = append(requirements, requirement.String())
+		}
+		assert.Equal(c.requirements, requirements, c.query)
+	}
+}
+
+func TestSatisfiableKeyAbsent(t *testing.T) {
+	assert := assert.New(t)
+
+	// by default `in` also matches label sets without the key.
+	selector, err := Parse("x in (a), x notin (a)")
+	assert.Nil(err)
+	ok, _ := Satisfiable(selector)
+	assert.True(ok)
+
+	selector, err = Parse("x in (a, b), x in (c), x")
+	assert.Nil(err)
+	ok, contradiction := Satisfiable(selector)
+	assert.False(ok)
+	assert.Len(contradiction.Requirements, 3)
+}
+
+func TestSatisfiableReason(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, _ := Parse("x=a,x=b")
+	_, contradiction := Satisfiable(selector)
+	assert.Equal(`x == a, x == b can't all match "x"`, contradiction.String())
+
+	_, contradiction = Satisfiable(In{Key: "x", RequireKey: true})
+	assert.Equal("x in () never matches", contradiction.String())
+
+	_, contradiction = Satisfiable(Or{And{Equals{Key: "x", Value: "a"}, Equals{Key: "x", Value: "b"}}, And{HasKey("y"), NotHasKey("y")}})
+	assert.Equal(`every alternative conflicts, including the first: x == a, x == b can't all match "x"`, contradiction.String())
+
+	_, contradiction = Satisfiable(Or{})
+	assert.Equal("an empty disjunction never matches", contradiction.String())
+}
+
+func TestSatisfiable(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, query := range []string{
+		"x in (a), !x",
+		"x in (), y",
+		"x notin (a), !x",
+		"x != a, x != b, x notin (c)",
+		"x, x notin (a, b)",
+		"x == a, y == a",
+		"x==",
+	} {
+		selector, err := Parse(query)
+		assert.Nil(err, query)
+		ok, contradiction := Satisfiable(selector)
+		assert.True(ok, query)
+		assert.Nil(contradiction, query)
+	}
+
+	strict, err := ParseWithOptions("x in (a), !x", Options{Semantics: SemanticsK8sStrict})
+	assert.Nil(err)
+	ok, _ := Satisfiable(strict)
+	assert.False(ok)
+
+	ok, _ = Satisfiable(And{})
+	assert.True(ok)
+}
+
+func TestSatisfiableExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		query       string
+		satisfiable bool
+	}{
+		{"x > 4, x < 5", true},
+		{"x > 4, x <= 4", false},
+		{"x >= 4, x <= 4", true},
+		{"x >= 4, x <= 4, x != 4", true}, // `4.0`
+		{"x >= 4, x <= 4, x notin (4, 4.0, 4.00)", true},
+		{"x > 4, x < 4.5, x != 4.25", true},
+		{"x > 4, x == a", false},
+		{"(x > 4 || x == a), x < 1", false},
+		{"x > 4 || x == a, x < 1", true},
+		{"!(x > 4), x == 5", false},
+		{"!(x > 4), x", true},
+		{"(x || y), !x, !y", false},
+		{"!(x || y), x", false},
+		{"!(x, y), x, y", false},
+		{"!(x, y), x", true},
+		{"x like web-*, x == web-1", true},
+		{"x like web-*, x == api-1", false},
+		{"x =~ web-[0-9]+, x !~ web-1", true},
+		{"x =~ web-[0-9]+, x == api", false},
+		{"x like web-*, x =~ web-.*", true},
+		{"x like (a*, b*), !x", false},
+	}
+	for _, c := range cases {
+		selector, err := ParseWithOptions(c.query, Options{Dialect: DialectExtended})
+		assert.Nil(err, c.query)
+		ok, _ := Satisfiable(selector)
+		assert.Equal(c.satisfiable, ok, c.query)
+	}
+}
+
+func TestSatisfiableUndecided(t *testing.T) {
+	assert := assert.New(t)
+
+	// the solver can't prove globs don't overlap, so assumes they do.
+	selector, err := ParseWithOptions("x like a*, x like b*", Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	ok, contradiction := Satisfiable(selector)
+	assert.True(ok)
+	assert.Nil(contradiction)
+
+	ok, _ = Satisfiable(And{customLeaf{}, HasKey("x"), NotHasKey("x")})
+	assert.True(ok)
+}
@@ -9,3 +9,14 @@ type Selector interface {
 	Validate() error
 	String() string
 }
+
+// Parent is implemented by selectors that combine other selectors, so `Walk` and `Rewrite` can traverse them.
+// Custom selector types may implement it to participate in traversals.
+type Parent interface {
+	Selector
+	// Children returns the combined selectors.
+	Children() []Selector
+	// WithChildren returns a copy of the selector combining the given selectors instead,
+	// or nil if there are none; an empty combination is removed rather than matching everything or nothing.
+	WithChildren(children []Selector) Selector
+}
@@ -0,0 +1,248 @@
+package selector
+
+// Simplify returns an equivalent selector without redundant requirements.
+// Every conjunction in the selector, including those within disjunctions and negations, is flattened and:
+//   - duplicate requirements are dropped
+//   - repeated `!=` and `notin` on one key are merged into a single `notin`
+//   - repeated `in` on one key are intersected
+//   - `==` and `in` on one key are folded into the `==`; if the value isn't in the set the pair can't
+//     match, and is reduced to `key == value, key in ()`
+//   - `key` is dropped when another requirement on the key already requires it to exist
+//
+// Case folded selectors are left as they are, because the rules above compare values exactly.
+// The order of the remaining requirements is preserved, and the input is not modified.
+func Simplify(s Selector) Selector {
+	if _, isFold := s.(Fold); isFold {
+		return s
+	}
+	if parent, isParent := s.(Parent); isParent && len(parent.Children()) > 0 {
+		children := parent.Children()
+		simplified := make([]Selector, 0, len(children))
+		for _, child := range children {
+			simplified = append(simplified, Simplify(child))
+		}
+		s = parent.WithChildren(simplified)
+	}
+	if typed, isAnd := s.(And); isAnd {
+		return simplifyAnd(typed)
+	}
+	return s
+}
+
+// simplifyAnd simplifies the requirements of a conjunction.
+func simplifyAnd(a And) Selector {
+	var flat []Selector
+	for _, child := range a {
+		if typed, isAnd := child.(And); isAnd {
+			flat = append(flat, typed...)
+			continue
+		}
+		flat = append(flat, child)
+	}
+
+	var children []Selector
+	for _, child := range flat {
+		if !containsSelector(children, child) {
+			children = append(children, child)
+		}
+	}
+
+	children = mergeNotIn(children)
+	children = intersectIn(children)
+	children = foldEqualsIn(children)
+	children = dropImpliedHasKey(children)
+
+	switch len(children) {
+	case 0:
+		return And{}
+	case 1:
+		return children[0]
+	}
+	return And(children)
+}
+
+// containsSelector returns if a list contains a selector structurally equal to the given one.
+func containsSelector(selectors []Selector, s Selector) bool {
+	for _, other := range selectors {
+		if EqualSelectors(other, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeNotIn merges the `!=` and `notin` requirements on each key into a `notin`
+// at the position of the first, if there is more than one.
+func mergeNotIn(children []Selector) []Selector {
+	counts := map[string]int{}
+	for _, child := range children {
+		switch typed := child.(type) {
+		case NotEquals:
+			counts[typed.Key]++
+		case NotIn:
+			counts[typed.Key]++
+		}
+	}
+
+	merged := map[string]int{}
+	var output []Selector
+	for _, child := range children {
+		var key string
+		var values []string
+		var rules valueRules
+		switch typed := child.(type) {
+		case NotEquals:
+			key, values, rules = typed.Key, []string{typed.Value}, typed.rules
+		case NotIn:
+			key, values, rules = typed.Key, typed.Values, typed.rules
+		default:
+			output = append(output, child)
+			continue
+		}
+		if counts[key] < 2 {
+			output = append(output, child)
+			continue
+		}
+		index, hasIndex := merged[key]
+		if !hasIndex {
+			merged[key] = len(output)
+			output = append(output, NotIn{Key: key, Values: appendUnique(nil, values...), rules: rules})
+			continue
+		}
+		existing := output[index].(NotIn)
+		output[index] = NotIn{Key: key, Values: appendUnique(existing.Values, values...), rules: existing.rules.union(rules)}
+	}
+	return output
+}
+
+// intersectIn intersects the `in` requirements on each key into the first of them.
+// The intersection requires the key if any of the requirements did.
+func intersectIn(children []Selector) []Selector {
+	first := map[string]int{}
+	var output []Selector
+	for _, child := range children {
+		typed, isIn := child.(In)
+		if !isIn {
+			output = append(output, child)
+			continue
+		}
+		index, hasIndex := first[typed.Key]
+		if !hasIndex {
+			first[typed.Key] = len(output)
+			output = append(output, child)
+			continue
+		}
+		existing := output[index].(In)
+		var values []string
+		for _, value := range existing.Values {
+			if containsString(typed.Values, value) {
+				values = append(values, value)
+			}
+		}
+		output[index] = In{Key: typed.Key, Values: values, RequireKey: existing.RequireKey || typed.RequireKey, rules: existing.rules.union(typed.rules)}
+	}
+	return output
+}
+
+// foldEqualsIn folds an `in` into an `==` on the same key, which is already at least as tight.
+// If the value isn't in the set the requirements can't both match, so the set is emptied.
+func foldEqualsIn(children []Selector) []Selector {
+	equals := map[string][]string{}
+	for _, child := range children {
+		if typed, isEquals := child.(Equals); isEquals {
+			equals[typed.Key] = append(equals[typed.Key], typed.Value)
+		}
+	}
+
+	var output []Selector
+	for _, child := range children {
+		typed, isIn := child.(In)
+		if !isIn {
+			output = append(output, child)
+			continue
+		}
+		values, hasEquals := equals[typed.Key]
+		if !hasEquals {
+			output = append(output, child)
+			continue
+		}
+		if containsAll(typed.Values, values) {
+			continue
+		}
+		output = append(output, In{Key: typed.Key, RequireKey: typed.RequireKey})
+	}
+	return output
+}
+
+// dropImpliedHasKey drops `key` requirements where another requirement needs the key to exist.
+func dropImpliedHasKey(children []Selector) []Selector {
+	required := map[string]bool{}
+	for _, child := range children {
+		if key, requiresKey := requiredKey(child); requiresKey {
+			required[key] = true
+		}
+	}
+
+	var output []Selector
+	for _, child := range children {
+		if typed, isHasKey := child.(HasKey); isHasKey && required[string(typed)] {
+			continue
+		}
+		output = append(output, child)
+	}
+	return output
+}
+
+// requiredKey returns the key of a requirement that only matches when its key exists, other than `HasKey`.
+func requiredKey(s Selector) (string, bool) {
+	switch typed := s.(type) {
+	case Equals:
+		return typed.Key, true
+	case In:
+		return typed.Key, typed.RequireKey
+	case GreaterThan:
+		return typed.Key, true
+	case GreaterThanOrEqual:
+		return typed.Key, true
+	case LessThan:
+		return typed.Key, true
+	case LessThanOrEqual:
+		return typed.Key, true
+	case Like:
+		return typed.Key, true
+	case Matches:
+		return typed.Key, true
+	}
+	return "", false
+}
+
+// appendUnique appends the values that aren't already in the slice.
+func appendUnique(values []string, additional ...string) []string {
+	output := append([]string(nil), values...)
+	for _, value := range additional {
+		if !containsString(output, value) {
+			output = append(output, value)
+		}
+	}
+	return output
+}
+
+// containsString returns if the values contain a value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAll returns if the values contain every one of the others.
+func containsAll(values []string, others []string) bool {
+	for _, other := range others {
+		if !containsString(values, other) {
+			return false
+		}
+	}
+	return true
+}
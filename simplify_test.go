@@ -0,0 +1,95 @@
+package selector
+
+import (
+	"math/rand"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestSimplify(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		query, expected string
+	}{
+		{"x == a, y, x == a", "x == a, y"},
+		{"x != a, y, x != b, x notin (c, a)", "x notin (a, b, c), y"},
+		{"x != a, y != b", "x != a, y != b"},
+		{"x in (a, b, c), y, x in (c, b, d)", "x in (b, c), y"},
+		{"x == a, x in (a, b)", "x == a"},
+		{"x in (b, c), x == a", "x in (), x == a"},
+		{"x, x == a, y, z, !z", "x == a, y, z, !z"},
+		{"x, x in (a)", "x, x in (a)"},
+		{"x, x notin (a)", "x, x notin (a)"},
+		{"x, x, x", "x"},
+	}
+	for _, c := range cases {
+		selector, err := Parse(c.query)
+		assert.Nil(err, c.query)
+		assert.Equal(c.expected, Simplify(selector).String(), c.query)
+	}
+}
+
+func TestSimplifyExtended(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseWithOptions("x, x > 4 || !(y, y =~ a.*, y =~ a.*), z like a*, z", Options{Dialect: DialectExtended})
+	assert.Nil(err)
+	assert.Equal("x > 4 || y !~ a.*, z like a*", Simplify(selector).String())
+}
+
+func TestSimplifyStrict(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseWithOptions("x, x in (a, b), x in (b)", Options{Semantics: SemanticsK8sStrict})
+	assert.Nil(err)
+	assert.Equal(In{Key: "x", Values: []string{"b"}, RequireKey: true}, Simplify(selector))
+
+	assert.Equal(In{Key: "x", Values: []string{"a"}, RequireKey: true}, Simplify(And{In{Key: "x", Values: []string{"a"}}, In{Key: "x", Values: []string{"a"}, RequireKey: true}}))
+	assert.Equal(And{}, Simplify(And{And{}}))
+}
+
+func TestSimplifyFold(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseWithOptions("x == a, x in (A, b)", Options{FoldCase: true})
+	assert.Nil(err)
+	assert.True(selector.Matches(Labels{"x": "a"}))
+	assert.True(Simplify(selector).Matches(Labels{"x": "a"}))
+
+	folded := Fold{Selector: And{In{Key: "x", Values: []string{"A"}}, In{Key: "x", Values: []string{"a"}}}}
+	assert.True(folded.Matches(Labels{"x": "a"}))
+	assert.True(Simplify(folded).Matches(Labels{"x": "a"}))
+
+	mixed := And{Fold{Selector: Equals{Key: "x", Value: "a"}}, Equals{Key: "x", Value: "a"}}
+	assert.False(mixed.Matches(Labels{"x": "A"}))
+	assert.False(Simplify(mixed).Matches(Labels{"x": "A"}))
+	assert.True(Simplify(mixed).Matches(Labels{"x": "a"}))
+}
+
+func TestSimplifyDoesNotModify(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := And{NotIn{Key: "x", Values: []string{"a"}}, NotEquals{Key: "x", Value: "b"}}
+	assert.Equal(NotIn{Key: "x", Values: []string{"a", "b"}}, Simplify(selector))
+	assert.Equal([]string{"a"}, selector[0].(NotIn).Values)
+}
+
+func TestSimplifyEquivalent(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(3))
+	for _, opts := range []Options{{}, {Semantics: SemanticsK8sStrict}, {FoldCase: true}} {
+		for index := 0; index < 1024; index++ {
+			query := generateSelector(r) + "," + generateSelector(r)
+			selector, err := ParseWithOptions(query, opts)
+			assert.Nil(err, query)
+			simplified := Simplify(selector)
+			for sample := 0; sample < 16; sample++ {
+				labels := generateLabels(r)
+				assert.Equal(selector.Matches(labels), simplified.Matches(labels), query, " => ", simplified.String(), " ", labels)
+			}
+		}
+	}
+}
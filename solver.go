@@ -0,0 +1,493 @@
+package selector
+
+import (
+	"fmt"
+	"math/big"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxClauses limits the size of the disjunctive normal form the solver expands a selector into.
+const maxClauses = 1 << 12
+
+// solution is the result of solving a selector.
+type solution int
+
+const (
+	// unsatisfiable means no label set matches the selector.
+	unsatisfiable solution = iota
+	// satisfiable means a label set matching the selector was found.
+	satisfiable
+	// undecided means no label set was found, but the selector could not be proven unsatisfiable,
+	// e.g. because it combines glob or regular expression patterns.
+	undecided
+)
+
+// atom is a single requirement, or its negation, in a clause.
+type atom struct {
+	selector Selector
+	key      string
+	negated  bool
+}
+
+// matches returns if the atom matches the state of its key.
+func (a atom) matches(value string, present bool) bool {
+	labels := Labels{}
+	if present {
+		labels[a.key] = value
+	}
+	return a.selector.Matches(labels) != a.negated
+}
+
+// requirement returns the selector for the atom.
+func (a atom) requirement() Selector {
+	if a.negated {
+		return Negate(a.selector)
+	}
+	return a.selector
+}
+
+// isPattern returns if the atom is a glob or regular expression, which the solver can't decide exactly.
+func (a atom) isPattern() bool {
+	switch a.selector.(type) {
+	case Like, Matches, NotMatches:
+		return true
+	}
+	return false
+}
+
+// isFinite returns if the atom only matches a present key with one of the values it mentions, if any,
+// so the candidates cover every value even alongside patterns.
+func (a atom) isFinite() bool {
+	switch a.selector.(type) {
+	case Equals, In, NotHasKey:
+		return !a.negated
+	case NotEquals, NotIn, HasKey:
+		return a.negated
+	}
+	return false
+}
+
+// clause is a conjunction of atoms.
+type clause []atom
+
+// toClauses returns the disjunctive normal form of a selector, or its negation, as a list of clauses.
+// It returns `ErrUnsupportedSelector` for selector types it can't reason about.
+func toClauses(s Selector, negated bool) ([]clause, error) {
+	switch typed := s.(type) {
+	case And:
+		if negated {
+			return orClauses(typed, true)
+		}
+		return andClauses(typed, false)
+	case Or:
+		if negated {
+			return andClauses(typed, true)
+		}
+		return orClauses(typed, false)
+	case Not:
+		if typed.Selector == nil {
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedSelector, s)
+		}
+		return toClauses(typed.Selector, !negated)
+	}
+	if key, rank := selectorKey(s); rank >= 0 {
+		return []clause{{{selector: s, key: key, negated: negated}}}, nil
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedSelector, s)
+}
+
+// andClauses returns the clauses for a conjunction, the cross product of the children's clauses.
+func andClauses(children []Selector, negated bool) ([]clause, error) {
+	output := []clause{{}}
+	for _, child := range children {
+		childClauses, err := toClauses(child, negated)
+		if err != nil {
+			return nil, err
+		}
+		if len(output)*len(childClauses) > maxClauses {
+			return nil, ErrUndecidable
+		}
+		var product []clause
+		for _, left := range output {
+			for _, right := range childClauses {
+				combined := make(clause, 0, len(left)+len(right))
+				product = append(product, append(append(combined, left...), right...))
+			}
+		}
+		output = product
+	}
+	return output, nil
+}
+
+// orClauses returns the clauses for a disjunction, the union of the children's clauses.
+func orClauses(children []Selector, negated bool) ([]clause, error) {
+	var output []clause
+	for _, child := range children {
+		childClauses, err := toClauses(child, negated)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, childClauses...)
+		if len(output) > maxClauses {
+			return nil, ErrUndecidable
+		}
+	}
+	return output, nil
+}
+
+// solve looks for a label set that matches a selector, returning the label set if one was found,
+// or a contradiction if the selector was proven unsatisfiable.
+func solve(s Selector) (solution, Labels, *Contradiction, error) {
+	clauses, err := toClauses(s, false)
+	if err != nil {
+		return undecided, nil, nil, err
+	}
+	if len(clauses) == 0 {
+		return unsatisfiable, nil, &Contradiction{Reason: "an empty disjunction never matches"}, nil
+	}
+
+	result := unsatisfiable
+	var contradiction *Contradiction
+	for _, c := range clauses {
+		clauseResult, labels, clauseContradiction := c.solve()
+		switch clauseResult {
+		case satisfiable:
+			return satisfiable, labels, nil, nil
+		case undecided:
+			result = undecided
+		default:
+			if contradiction == nil {
+				contradiction = clauseContradiction
+			}
+		}
+	}
+	if result == undecided {
+		return undecided, nil, nil, nil
+	}
+	if len(clauses) > 1 {
+		contradiction.Reason = fmt.Sprintf("every alternative conflicts, including the first: %s", contradiction.Reason)
+	}
+	return unsatisfiable, nil, contradiction, nil
+}
+
+// solve looks for a label set that matches every atom in the clause.
+// Keys are independent, so each key is solved on its own.
+func (c clause) solve() (solution, Labels, *Contradiction) {
+	var keys []string
+	byKey := map[string][]atom{}
+	for _, a := range c {
+		if _, hasKey := byKey[a.key]; !hasKey {
+			keys = append(keys, a.key)
+		}
+		byKey[a.key] = append(byKey[a.key], a)
+	}
+
+	result := satisfiable
+	labels := Labels{}
+	for _, key := range keys {
+		keyResult, value, present := solveKey(byKey[key])
+		switch keyResult {
+		case unsatisfiable:
+			return unsatisfiable, nil, newContradiction(key, minimizeConflict(byKey[key]))
+		case undecided:
+			result = undecided
+		default:
+			if present {
+				labels[key] = value
+			}
+		}
+	}
+	if result == undecided {
+		return undecided, nil, nil
+	}
+	return satisfiable, labels, nil
+}
+
+// solveKey looks for a state of a single key, either absent or a value, that matches every atom.
+// Without glob or regular expression atoms, or with an atom that limits the key to a few values,
+// the candidates cover every distinct behavior of the atoms, so failing to find a state proves there isn't one.
+func solveKey(atoms []atom) (result solution, value string, present bool) {
+	if atomsMatch(atoms, "", false) {
+		return satisfiable, "", false
+	}
+	values := candidates(atoms)
+	// prefer values kubernetes accepts, so the label sets found are valid.
+	sort.SliceStable(values, func(i, j int) bool {
+		return CheckValue(values[i]) == nil && CheckValue(values[j]) != nil
+	})
+	for _, candidate := range values {
+		if atomsMatch(atoms, candidate, true) {
+			return satisfiable, candidate, true
+		}
+	}
+	var hasPattern bool
+	for _, a := range atoms {
+		if a.isFinite() {
+			return unsatisfiable, "", false
+		}
+		hasPattern = hasPattern || a.isPattern()
+	}
+	if hasPattern {
+		return undecided, "", false
+	}
+	return unsatisfiable, "", false
+}
+
+// atomsMatch returns if every atom matches a state.
+func atomsMatch(atoms []atom, value string, present bool) bool {
+	for _, a := range atoms {
+		if !a.matches(value, present) {
+			return false
+		}
+	}
+	return true
+}
+
+// minimizeConflict returns a minimal subset of unsatisfiable atoms that is still unsatisfiable.
+func minimizeConflict(atoms []atom) []atom {
+	conflict := append([]atom(nil), atoms...)
+	for index := 0; index < len(conflict); {
+		without := append(append([]atom(nil), conflict[:index]...), conflict[index+1:]...)
+		if result, _, _ := solveKey(without); result == unsatisfiable {
+			conflict = without
+			continue
+		}
+		index++
+	}
+	return conflict
+}
+
+// candidates returns the values to try for a key. The atoms only distinguish values by equality with
+// the literals they mention and by numeric order relative to the numeric literals, so the candidates
+// are the literals, an alternative spelling of each numeric literal, a number between and beyond each
+// pair of numeric literals, zero, and a value equal to no literal. Patterns add values they match.
+func candidates(atoms []atom) []string {
+	literals := map[string]bool{}
+	var ordered []string
+	add := func(values ...string) {
+		for _, value := range values {
+			if !literals[value] {
+				literals[value] = true
+				ordered = append(ordered, value)
+			}
+		}
+	}
+
+	var patterns []string
+	for _, a := range atoms {
+		switch typed := a.selector.(type) {
+		case Equals:
+			add(typed.Value)
+		case NotEquals:
+			add(typed.Value)
+		case In:
+			add(typed.Values...)
+		case NotIn:
+			add(typed.Values...)
+		case GreaterThan:
+			add(typed.Value)
+		case GreaterThanOrEqual:
+			add(typed.Value)
+		case LessThan:
+			add(typed.Value)
+		case LessThanOrEqual:
+			add(typed.Value)
+		case Like:
+			for _, pattern := range typed.Patterns {
+				patterns = append(patterns, globWitnesses(pattern)...)
+			}
+		case Matches:
+			patterns = append(patterns, regexpWitnesses(typed.Pattern)...)
+		case NotMatches:
+			patterns = append(patterns, regexpWitnesses(typed.Pattern)...)
+		}
+	}
+
+	var numbers []string
+	for _, literal := range ordered {
+		if isNumeric(literal) {
+			numbers = append(numbers, literal)
+		}
+	}
+	output := append([]string(nil), ordered...)
+	for _, number := range numbers {
+		output = append(output, respell(number, literals))
+	}
+	output = append(output, numericCandidates(numbers)...)
+	output = append(output, "", fresh(literals))
+	return append(output, patterns...)
+}
+
+// respell returns a numerically equal spelling of a number that isn't one of the literals, e.g. `4.0` for `4`.
+func respell(number string, literals map[string]bool) string {
+	if !strings.ContainsRune(number, Dot) {
+		number += string(Dot)
+	}
+	for {
+		number += "0"
+		if !literals[number] {
+			return number
+		}
+	}
+}
+
+// numericCandidates returns a number below, between and above the numbers, and zero.
+// Numbers strictly between two consecutive numbers compare the same way to every number,
+// so the midpoint stands for all of them.
+func numericCandidates(numbers []string) []string {
+	if len(numbers) == 0 {
+		return []string{"0"}
+	}
+	rationals := make([]*big.Rat, 0, len(numbers))
+	for _, number := range numbers {
+		if rational, ok := new(big.Rat).SetString(number); ok {
+			rationals = append(rationals, rational)
+		}
+	}
+	sort.Slice(rationals, func(i, j int) bool {
+		return rationals[i].Cmp(rationals[j]) < 0
+	})
+
+	one := big.NewRat(1, 1)
+	two := big.NewRat(2, 1)
+	output := []string{
+		formatRat(new(big.Rat).Sub(rationals[0], one)),
+		formatRat(new(big.Rat).Add(rationals[len(rationals)-1], one)),
+	}
+	for index := 1; index < len(rationals); index++ {
+		if rationals[index-1].Cmp(rationals[index]) == 0 {
+			continue
+		}
+		sum := new(big.Rat).Add(rationals[index-1], rationals[index])
+		output = append(output, formatRat(sum.Quo(sum, two)))
+	}
+	// negative numbers aren't valid label values, so zero is the valid value most likely to be in range.
+	return append(output, "0")
+}
+
+// formatRat formats a rational with a finite decimal expansion as a decimal.
+func formatRat(rational *big.Rat) string {
+	if rational.IsInt() {
+		return rational.RatString()
+	}
+	for precision := 1; ; precision++ {
+		formatted := rational.FloatString(precision)
+		if parsed, ok := new(big.Rat).SetString(formatted); ok && parsed.Cmp(rational) == 0 {
+			return formatted
+		}
+	}
+}
+
+// fresh returns a non-numeric value that isn't one of the literals.
+func fresh(literals map[string]bool) string {
+	value := "x"
+	for literals[value] {
+		value += "x"
+	}
+	return value
+}
+
+// globWitnesses returns values that match a glob pattern, if they can be found.
+func globWitnesses(pattern string) (output []string) {
+	for _, star := range []string{"", "x"} {
+		var witness strings.Builder
+		for pos := 0; pos < len(pattern); {
+			ch, width := utf8.DecodeRuneInString(pattern[pos:])
+			switch ch {
+			case Star:
+				witness.WriteString(star)
+			case QuestionMark:
+				witness.WriteRune('x')
+			case BackSlash:
+				if pos+width < len(pattern) {
+					escaped, escapedWidth := utf8.DecodeRuneInString(pattern[pos+width:])
+					witness.WriteRune(escaped)
+					width += escapedWidth
+				}
+			case OpenBracket:
+				next, ok := skipGlobClass(pattern, pos)
+				if !ok {
+					return
+				}
+				witness.WriteRune(classWitness(pattern, pos))
+				width = next - pos
+			default:
+				witness.WriteRune(ch)
+			}
+			pos += width
+		}
+		if globMatch(pattern, witness.String()) {
+			output = append(output, witness.String())
+		}
+	}
+	return
+}
+
+// classWitness returns a rune matched by the glob character class at a position, if one of a few common runes is.
+func classWitness(pattern string, pos int) rune {
+	for _, ch := range "xa0Z-_. " {
+		if matched, _, ok := matchGlobClassCase(pattern, pos, ch, false); ok && matched {
+			return ch
+		}
+	}
+	if ch, _ := utf8.DecodeRuneInString(pattern[pos+1:]); ch != Bang && ch != Caret {
+		return ch
+	}
+	return 'x'
+}
+
+// regexpWitnesses returns values that match a regular expression, if they can be found.
+func regexpWitnesses(pattern string) []string {
+	compiled, err := compilePattern(pattern)
+	if err != nil {
+		return nil
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	var output []string
+	for _, witness := range []string{regexpWitness(parsed.Simplify(), false), regexpWitness(parsed.Simplify(), true)} {
+		if compiled.MatchString(witness) {
+			output = append(output, witness)
+		}
+	}
+	return output
+}
+
+// regexpWitness returns a string a regular expression is likely to match; the shortest choice at each
+// repetition, or a longer one if `repeat` is set, and the first alternative.
+func regexpWitness(re *syntax.Regexp, repeat bool) string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return string(re.Rune)
+	case syntax.OpCharClass:
+		if len(re.Rune) > 0 {
+			return string(re.Rune[0])
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return "x"
+	case syntax.OpCapture:
+		return regexpWitness(re.Sub[0], repeat)
+	case syntax.OpStar, syntax.OpQuest:
+		if repeat {
+			return regexpWitness(re.Sub[0], repeat)
+		}
+	case syntax.OpPlus:
+		return regexpWitness(re.Sub[0], repeat)
+	case syntax.OpRepeat:
+		return strings.Repeat(regexpWitness(re.Sub[0], repeat), re.Min)
+	case syntax.OpConcat:
+		var witness strings.Builder
+		for _, sub := range re.Sub {
+			witness.WriteString(regexpWitness(sub, repeat))
+		}
+		return witness.String()
+	case syntax.OpAlternate:
+		return regexpWitness(re.Sub[0], repeat)
+	}
+	return ""
+}
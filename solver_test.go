@@ -0,0 +1,92 @@
+package selector
+
+import (
+	"math/rand"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestSolveWitnessesMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(4))
+	for _, opts := range []Options{{}, {Semantics: SemanticsK8sStrict}} {
+		for index := 0; index < 1024; index++ {
+			query := generateSelector(r) + "," + generateSelector(r)
+			selector, err := ParseWithOptions(query, opts)
+			assert.Nil(err, query)
+
+			result, labels, contradiction, err := solve(selector)
+			assert.Nil(err)
+			switch result {
+			case satisfiable:
+				assert.True(selector.Matches(labels), query, " ", labels)
+			case unsatisfiable:
+				assert.NotNil(contradiction)
+				for sample := 0; sample < 64; sample++ {
+					labels := generateLabels(r)
+					assert.False(selector.Matches(labels), query, " ", labels)
+				}
+			default:
+				assert.Equal(satisfiable, result, query)
+			}
+		}
+	}
+}
+
+func TestSolveNegatedWitnessesMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(5))
+	for index := 0; index < 512; index++ {
+		query := generateSelector(r)
+		selector, err := Parse(query)
+		assert.Nil(err, query)
+		negated := Not{Selector: selector}
+
+		result, labels, _, err := solve(negated)
+		assert.Nil(err)
+		if result == satisfiable {
+			assert.True(negated.Matches(labels), query, " ", labels)
+		}
+	}
+}
+
+func TestCandidatesNumeric(t *testing.T) {
+	assert := assert.New(t)
+
+	atoms := []atom{
+		{selector: GreaterThan{Key: "x", Value: "4"}, key: "x"},
+		{selector: LessThan{Key: "x", Value: "-1.5"}, key: "x"},
+	}
+	values := candidates(atoms)
+	assert.Equal([]string{"4", "-1.5", "4.0", "-1.50", "-2.5", "5", "1.25", "0", "", "x"}, values)
+}
+
+func TestToClauses(t *testing.T) {
+	assert := assert.New(t)
+
+	clauses, err := toClauses(And{Or{HasKey("a"), HasKey("b")}, Or{HasKey("c"), HasKey("d")}}, false)
+	assert.Nil(err)
+	assert.Len(clauses, 4)
+
+	clauses, err = toClauses(And{HasKey("a"), HasKey("b")}, true)
+	assert.Nil(err)
+	assert.Len(clauses, 2)
+	assert.True(clauses[0][0].negated)
+
+	_, err = toClauses(Fold{Selector: HasKey("a")}, false)
+	assert.NotNil(err)
+}
+
+func TestPatternWitnesses(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"web-", "web-x"}, globWitnesses("web-*"))
+	assert.Equal([]string{"a0x", "a0xx"}, globWitnesses("a[0-9]?*"))
+	assert.Equal([]string{"a-b", "a-xb"}, globWitnesses(`a\-*b`))
+	assert.Equal([]string{"web-0", "web-0"}, regexpWitnesses("web-[0-9]+"))
+	assert.Equal([]string{"ab", "aab"}, regexpWitnesses("a*a(b|c)"))
+	assert.Nil(regexpWitnesses("("))
+}
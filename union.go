@@ -0,0 +1,81 @@
+package selector
+
+// Union returns a selector matching the label sets either selector matches.
+// Where an exact conjunction exists it is returned instead of a disjunction:
+//   - if one selector implies the other, the wider of the two is returned
+//   - if the selectors share every requirement but one on the same key, those are merged,
+//     e.g. `x in (a)` and `x in (b)` give `x in (a, b)`
+//
+// Otherwise the result is an `Or`, which needs the extended dialect to parse.
+// A union that matches every label set, such as that of `x` and `!x`, is an empty `And`, which prints as ""
+// and only parses back with `Options{AllowEmpty: true}`.
+func Union(a, b Selector) Selector {
+	if implies, err := Implies(a, b); err == nil && implies {
+		return b
+	}
+	if implies, err := Implies(b, a); err == nil && implies {
+		return a
+	}
+	if merged, ok := mergeConjunctions(a, b); ok {
+		return merged
+	}
+	return liftOr(a, b)
+}
+
+// mergeConjunctions merges two conjunctions that differ in one requirement on the same key.
+func mergeConjunctions(a, b Selector) (Selector, bool) {
+	left, right := conjuncts(Simplify(a)), conjuncts(Simplify(b))
+
+	var common []Selector
+	var leftRest, rightRest []Selector
+	position := -1
+	for _, s := range left {
+		if containsSelector(right, s) {
+			common = append(common, s)
+			continue
+		}
+		position = len(common)
+		leftRest = append(leftRest, s)
+	}
+	for _, s := range right {
+		if !containsSelector(left, s) {
+			rightRest = append(rightRest, s)
+		}
+	}
+	if len(leftRest) != 1 || len(rightRest) != 1 {
+		return nil, false
+	}
+
+	x, isSet := toValueSet(leftRest[0])
+	y, isOtherSet := toValueSet(rightRest[0])
+	if !isSet || !isOtherSet || x.key != y.key {
+		return nil, false
+	}
+	var strictIn, compatibleIn bool
+	for _, s := range []Selector{leftRest[0], rightRest[0]} {
+		if typed, isIn := s.(In); isIn {
+			strictIn = strictIn || typed.RequireKey
+			compatibleIn = compatibleIn || !typed.RequireKey
+		}
+	}
+	merged, ok := x.union(y).selector(strictIn, compatibleIn)
+	if !ok {
+		return nil, false
+	}
+
+	output := append(And{}, common[:position]...)
+	output = append(output, conjuncts(merged)...)
+	output = append(output, common[position:]...)
+	if len(output) == 1 {
+		return output[0], true
+	}
+	return output, true
+}
+
+// conjuncts returns the requirements of a conjunction, or the selector itself.
+func conjuncts(s Selector) []Selector {
+	if typed, isAnd := s.(And); isAnd {
+		return typed
+	}
+	return []Selector{s}
+}
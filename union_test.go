@@ -0,0 +1,99 @@
+package selector
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestUnion(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		a, b     string
+		expected string
+	}{
+		{"x in (a)", "x in (b)", "x in (a, b)"},
+		{"x=a", "x=b", "x in (a, b), x"},
+		{"x=a", "x in (a, b)", "x in (a, b)"},
+		{"x in (a, b)", "x=a", "x in (a, b)"},
+		{"x != a", "x != b", ""},
+		{"x notin (a, b)", "x notin (b, c)", "x != b"},
+		{"x notin (a, b)", "x=a", "x != b"},
+		{"x", "!x", ""},
+		{"env=prod, team=a", "team=b, env=prod", "env == prod, team in (a, b), team"},
+		{"env=prod, team in (a)", "env=prod, team in (b)", "env == prod, team in (a, b)"},
+		{"env=prod, team=a", "env=prod", "env == prod"},
+		{"x=a", "y=b", "x == a || y == b"},
+		{"env=prod, team=a", "env=dev, team=b", "env == prod, team == a || env == dev, team == b"},
+		{"!x", "x=a", "!x || x == a"},
+	}
+	for _, c := range cases {
+		a, err := Parse(c.a)
+		assert.Nil(err, c.a)
+		b, err := Parse(c.b)
+		assert.Nil(err, c.b)
+		assert.Equal(c.expected, Union(a, b).String(), c.a, " | ", c.b)
+	}
+}
+
+func TestUnionMatchesEverything(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, pair := range [][2]Selector{
+		{NotEquals{Key: "x", Value: "a"}, NotEquals{Key: "x", Value: "b"}},
+		{HasKey("x"), NotHasKey("x")},
+	} {
+		union := Union(pair[0], pair[1])
+		assert.Equal(And{}, union)
+		assert.True(union.Matches(Labels{}))
+		assert.True(union.Matches(Labels{"x": "a"}))
+
+		_, err := Parse(union.String())
+		assert.True(errors.Is(err, ErrEmptySelector))
+		reparsed, err := ParseWithOptions(union.String(), Options{AllowEmpty: true})
+		assert.Nil(err)
+		assert.Equal(union, reparsed)
+	}
+}
+
+func TestUnionK8sStrict(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := Options{Semantics: SemanticsK8sStrict}
+	a, _ := ParseWithOptions("x in (a)", opts)
+	b, _ := ParseWithOptions("x = b", opts)
+	union := Union(a, b)
+	assert.Equal(In{Key: "x", Values: []string{"a", "b"}, RequireKey: true}, union)
+
+	reparsed, err := ParseWithOptions(union.String(), opts)
+	assert.Nil(err)
+	assert.Equal(union, reparsed)
+}
+
+func TestUnionMatchesAndReparses(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(7))
+	for _, semantics := range []Semantics{SemanticsCompatible, SemanticsK8sStrict} {
+		opts := Options{Dialect: DialectExtended, Semantics: semantics, AllowEmpty: true}
+		for index := 0; index < 512; index++ {
+			a, err := ParseWithOptions(generateSelector(r), opts)
+			assert.Nil(err)
+			b, err := ParseWithOptions(generateSelector(r), opts)
+			assert.Nil(err)
+			union := Union(a, b)
+			reparsed, err := ParseWithOptions(union.String(), opts)
+			assert.Nil(err, union)
+
+			for sample := 0; sample < 64; sample++ {
+				labels := generateLabels(r)
+				expected := a.Matches(labels) || b.Matches(labels)
+				assert.Equal(expected, union.Matches(labels), a, " | ", b, " ", labels)
+				assert.Equal(expected, reparsed.Matches(labels), union, " ", labels)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package selector
 
 import (
 	"fmt"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -25,6 +26,10 @@ const (
 	BackTick = rune('`')
 	// Bang is a common rune.
 	Bang = rune('!')
+	// DoubleQuote is a common rune.
+	DoubleQuote = rune('"')
+	// SingleQuote is a common rune.
+	SingleQuote = rune('\'')
 	// Comma is a common rune.
 	Comma = rune(',')
 	// OpenBracket is a common rune.
@@ -37,12 +42,22 @@ const (
 	CloseBracket = rune(']')
 	// CloseParens is a common rune.
 	CloseParens = rune(')')
+	// OpenAngle is a common rune.
+	OpenAngle = rune('<')
+	// CloseAngle is a common rune.
+	CloseAngle = rune('>')
 	// Equal is a common rune.
 	Equal = rune('=')
 	// Space is a common rune.
 	Space = rune(' ')
 	// Tab is a common rune.
 	Tab = rune('\t')
+	// Star is a common rune.
+	Star = rune('*')
+	// QuestionMark is a common rune.
+	QuestionMark = rune('?')
+	// Pipe is a common rune.
+	Pipe = rune('|')
 	// Tilde is a common rune.
 	Tilde = rune('~')
 	// CarriageReturn is a common rune.
@@ -73,23 +88,55 @@ var (
 	// ErrKeyDNSPrefixTooLong indicates a key's "dns" prefix is empty.
 	ErrKeyDNSPrefixTooLong = fmt.Errorf("key dns prefix too long; must be less than 253 characters")
 
-	// ErrValueTooLong indicates a value is too long.
-	ErrValueTooLong = fmt.Errorf("value too long; must be less than 63 characters")
+	// ErrValueTooLong indicates a value is longer than the value length limit, `MaxValueLen` or `Options.MaxValueLen`.
+	ErrValueTooLong = fmt.Errorf("value too long")
+
+	// ErrValueNotNumeric indicates a value is not an integer or decimal.
+	ErrValueNotNumeric = fmt.Errorf("value is not an integer or decimal")
+
+	// ErrInvalidPattern indicates a regular expression could not be compiled.
+	ErrInvalidPattern = fmt.Errorf("invalid regular expression")
+
+	// ErrInvalidGlob indicates a glob pattern is malformed.
+	ErrInvalidGlob = fmt.Errorf("invalid glob pattern")
+
+	// ErrEmptyValueSet is returned under k8s-strict semantics for `in` or `notin` with no values.
+	ErrEmptyValueSet = fmt.Errorf("values set can't be empty")
+
+	// ErrValueCount is returned for a requirement with the wrong number of values for its operator.
+	ErrValueCount = fmt.Errorf("wrong number of values for operator")
+
+	// ErrUnsupportedSelector is returned for selector types from other packages where their structure is needed.
+	ErrUnsupportedSelector = fmt.Errorf("unsupported selector type")
+
+	// ErrUndecidable is returned when a question about selectors can't be answered exactly,
+	// e.g. whether combinations of glob or regular expression patterns overlap.
+	ErrUndecidable = fmt.Errorf("can't be decided exactly")
+
+	// ErrNoWitness is returned when no valid label set with the requested outcome exists.
+	ErrNoWitness = fmt.Errorf("no label set found")
 
 	// ErrKeyInvalidCharacter indicates a key contains characters
 	ErrKeyInvalidCharacter = fmt.Errorf(`key contains invalid characters, regex used: ([A-Za-z0-9_-\.])`)
 
-	// MaxDNSPrefixLen is the maximum dns prefix length.
-	MaxDNSPrefixLen = 253
-	// MaxKeyLen is the maximum key length.
-	MaxKeyLen = 63
-	// MaxValueLen is the maximum value length.
-	MaxValueLen = 63
+	// MaxDNSPrefixLen is the maximum dns prefix length used by `CheckKey`.
+	MaxDNSPrefixLen = defaultMaxDNSPrefixLen
+	// MaxKeyLen is the maximum key length used by `CheckKey`.
+	MaxKeyLen = defaultMaxKeyLen
+	// MaxValueLen is the maximum value length used by `CheckValue` and `CheckNumber`.
+	MaxValueLen = defaultMaxValueLen
 
 	// MaxKeyTotalLen is the maximum total key length.
 	MaxKeyTotalLen = MaxDNSPrefixLen + MaxKeyLen + 1
 )
 
+// The default limits, which the parser uses rather than reading the mutable package level limits.
+const (
+	defaultMaxDNSPrefixLen = 253
+	defaultMaxKeyLen       = 63
+	defaultMaxValueLen     = 63
+)
+
 // ValidateLabels validates all the keys and values for the label set.
 func ValidateLabels(labels Labels) (err error) {
 	for key, value := range labels {
@@ -107,12 +154,17 @@ func ValidateLabels(labels Labels) (err error) {
 
 // CheckKey validates a key.
 func CheckKey(key string) (err error) {
+	return checkKey(key, MaxKeyTotalLen, MaxKeyLen)
+}
+
+// checkKey validates a key against the given total and name length limits.
+func checkKey(key string, maxTotalLen, maxLen int) (err error) {
 	keyLen := len(key)
 	if keyLen == 0 {
 		err = ErrKeyEmpty
 		return
 	}
-	if keyLen > MaxKeyTotalLen {
+	if keyLen > maxTotalLen {
 		err = ErrKeyTooLong
 		return
 	}
@@ -126,7 +178,7 @@ func CheckKey(key string) (err error) {
 		switch state {
 		case 0: // collect dns prefix or key
 			if ch == ForwardSlash {
-				err = checkDNS(string(working))
+				err = checkDNS(string(working), maxTotalLen-maxLen-1)
 				if err != nil {
 					return
 				}
@@ -142,7 +194,7 @@ func CheckKey(key string) (err error) {
 	if len(working) == 0 {
 		return ErrKeyEmpty
 	}
-	if len(working) > MaxKeyLen {
+	if len(working) > maxLen {
 		return ErrKeyTooLong
 	}
 
@@ -151,12 +203,33 @@ func CheckKey(key string) (err error) {
 
 // CheckValue returns if the value is valid.
 func CheckValue(value string) error {
-	if len(value) > MaxValueLen {
+	return checkValue(value, MaxValueLen)
+}
+
+// checkValue returns if the value is valid and within the given length limit.
+func checkValue(value string, maxLen int) error {
+	if len(value) > maxLen {
 		return ErrValueTooLong
 	}
 	return checkName(value)
 }
 
+// CheckNumber returns if the value is a valid integer or decimal for the numeric comparison selectors.
+func CheckNumber(value string) error {
+	return checkNumber(value, MaxValueLen)
+}
+
+// checkNumber returns if the value is a valid integer or decimal within the given length limit.
+func checkNumber(value string, maxLen int) error {
+	if len(value) > maxLen {
+		return ErrValueTooLong
+	}
+	if !isNumeric(value) {
+		return ErrValueNotNumeric
+	}
+	return nil
+}
+
 func checkName(value string) (err error) {
 	valueLen := len(value)
 	var state int
@@ -186,13 +259,14 @@ func checkName(value string) (err error) {
 	return
 }
 
-func checkDNS(value string) (err error) {
+// checkDNS validates a dns prefix against the given length limit.
+func checkDNS(value string, maxLen int) (err error) {
 	valueLen := len(value)
 	if valueLen == 0 {
 		err = ErrKeyDNSPrefixEmpty
 		return
 	}
-	if valueLen > MaxDNSPrefixLen {
+	if valueLen > maxLen {
 		err = ErrKeyDNSPrefixTooLong
 		return
 	}
@@ -277,3 +351,155 @@ func isLowerAlpha(ch rune) bool {
 func isAlpha(ch rune) bool {
 	return !isWhitespace(ch) && !unicode.IsControl(ch) && !isSymbol(ch)
 }
+
+// quoteValue returns the value as it should be written in a selector, quoting it
+// (in the extended dialect form) if it contains runes that would otherwise be read as syntax.
+// Values the default dialect accepts are written bare so they parse there, unless they contain a backslash,
+// which the extended dialect could read as an escape, e.g. a trailing `\`.
+func quoteValue(value string) string {
+	if checkName(value) == nil && !strings.ContainsRune(value, BackSlash) {
+		return value
+	}
+	for _, ch := range value {
+		if needsQuote(ch) {
+			return quoteString(value)
+		}
+	}
+	return value
+}
+
+// needsQuote returns if a rune would be read as syntax (or whitespace) in an unquoted value.
+func needsQuote(ch rune) bool {
+	if isWhitespace(ch) || unicode.IsControl(ch) || isSelectorSymbol(ch) {
+		return true
+	}
+	switch ch {
+	case DoubleQuote, SingleQuote, BackSlash, Pipe, OpenAngle, CloseAngle:
+		return true
+	}
+	return false
+}
+
+// quoteSetValue returns the value as it should be written in a set of values.
+// Empty values are quoted so they are not elided; the default dialect has no way to write them.
+func quoteSetValue(value string) string {
+	if len(value) == 0 {
+		return quoteString(value)
+	}
+	return quoteValue(value)
+}
+
+// quoteString double quotes a value, escaping backslashes and double quotes.
+func quoteString(value string) string {
+	var output strings.Builder
+	output.WriteRune(DoubleQuote)
+	for _, ch := range value {
+		if ch == DoubleQuote || ch == BackSlash {
+			output.WriteRune(BackSlash)
+		}
+		output.WriteRune(ch)
+	}
+	output.WriteRune(DoubleQuote)
+	return output.String()
+}
+
+// quotePattern returns a pattern as it should be written in a selector. Patterns are read as written,
+// so they are only quoted if they contain runes that would otherwise be read as syntax.
+func quotePattern(pattern string) string {
+	for _, ch := range pattern {
+		if ch != BackSlash && needsQuote(ch) {
+			return quotePatternString(pattern)
+		}
+	}
+	if endsInEscape(pattern) {
+		return quotePatternString(pattern)
+	}
+	return pattern
+}
+
+// quoteSetPattern returns a pattern as it should be written in a set of patterns; empty patterns are quoted.
+func quoteSetPattern(pattern string) string {
+	if len(pattern) == 0 {
+		return quotePatternString(pattern)
+	}
+	return quotePattern(pattern)
+}
+
+// quoteSetPatterns quotes each of a set of patterns and joins them with commas.
+func quoteSetPatterns(patterns []string) string {
+	quoted := make([]string, len(patterns))
+	for index, pattern := range patterns {
+		quoted[index] = quoteSetPattern(pattern)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// quotePatternString quotes a pattern, escaping only the quote. Double quotes are used unless the pattern
+// can only be read back exactly in single quotes, i.e. if it contains `\"`.
+func quotePatternString(pattern string) string {
+	quote := DoubleQuote
+	if !canQuotePattern(pattern, DoubleQuote) && canQuotePattern(pattern, SingleQuote) {
+		quote = SingleQuote
+	}
+	var output strings.Builder
+	output.WriteRune(quote)
+	for pos := 0; pos < len(pattern); pos++ {
+		switch pattern[pos] {
+		case byte(BackSlash):
+			output.WriteByte(pattern[pos])
+			if pos+1 < len(pattern) {
+				pos++
+				output.WriteByte(pattern[pos])
+			}
+		case byte(quote):
+			output.WriteRune(BackSlash)
+			output.WriteRune(quote)
+		default:
+			output.WriteByte(pattern[pos])
+		}
+	}
+	output.WriteRune(quote)
+	return output.String()
+}
+
+// canQuotePattern returns if a pattern reads back exactly within a quote, which it can't if it has a backslash
+// before the quote or at its end, as those would be read as an escaped quote.
+func canQuotePattern(pattern string, quote rune) bool {
+	if endsInEscape(pattern) {
+		return false
+	}
+	for pos := 0; pos < len(pattern)-1; pos++ {
+		if pattern[pos] != byte(BackSlash) {
+			continue
+		}
+		if pattern[pos+1] == byte(quote) {
+			return false
+		}
+		pos++
+	}
+	return true
+}
+
+// endsInEscape returns if a pattern ends in a backslash that doesn't escape another backslash,
+// which would escape whatever follows the pattern.
+func endsInEscape(pattern string) bool {
+	var count int
+	for pos := len(pattern) - 1; pos >= 0 && pattern[pos] == byte(BackSlash); pos-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// isQuoteRune returns if a rune opens a quoted value in the extended dialect.
+func isQuoteRune(ch rune) bool {
+	return ch == DoubleQuote || ch == SingleQuote
+}
+
+// quoteSetValues quotes each of a set of values and joins them with commas.
+func quoteSetValues(values []string) string {
+	quoted := make([]string, len(values))
+	for index, value := range values {
+		quoted[index] = quoteSetValue(value)
+	}
+	return strings.Join(quoted, ", ")
+}
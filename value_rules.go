@@ -0,0 +1,53 @@
+package selector
+
+// valueRules are the rules the values of a selector were checked against when it was parsed,
+// so that `Validate` accepts the values the parser accepted.
+// The zero value is the kubernetes value rules with the package level `MaxValueLen`.
+type valueRules struct {
+	// extended only checks the length of values, as the extended dialect does.
+	extended bool
+	// maxLen is the value length limit; zero uses `MaxValueLen`.
+	maxLen int
+}
+
+// valueRulesFor returns the rules of the given options for a selector's values,
+// or the zero value if every value is valid under the kubernetes rules anyway.
+func valueRulesFor(opts Options, values ...string) valueRules {
+	for _, value := range values {
+		if checkValue(value, defaultMaxValueLen) != nil {
+			return valueRules{extended: opts.Extended(), maxLen: opts.valueLimit()}
+		}
+	}
+	return valueRules{}
+}
+
+// check validates a value under the rules.
+func (r valueRules) check(value string) error {
+	if !r.extended {
+		return checkValue(value, r.limit())
+	}
+	if len(value) > r.limit() {
+		return ErrValueTooLong
+	}
+	return nil
+}
+
+// limit returns the value length limit.
+func (r valueRules) limit() int {
+	if r.maxLen == 0 {
+		return MaxValueLen
+	}
+	return r.maxLen
+}
+
+// union returns rules that accept the values either rules accept, for a selector combining the values of two.
+func (r valueRules) union(other valueRules) valueRules {
+	if r == (valueRules{}) && other == (valueRules{}) {
+		return r
+	}
+	output := valueRules{extended: r.extended || other.extended, maxLen: r.limit()}
+	if other.limit() > output.maxLen {
+		output.maxLen = other.limit()
+	}
+	return output
+}
@@ -0,0 +1,65 @@
+package selector
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestValueRulesCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(valueRules{}.check("a.b-c"))
+	assert.True(errors.Is(valueRules{}.check("a b"), ErrKeyInvalidCharacter))
+	assert.True(errors.Is(valueRules{}.check(strings.Repeat("a", 64)), ErrValueTooLong))
+
+	assert.Nil(valueRules{extended: true}.check("a b"))
+	assert.True(errors.Is(valueRules{extended: true, maxLen: 2}.check("abc"), ErrValueTooLong))
+	assert.Nil(valueRules{maxLen: 100}.check(strings.Repeat("a", 64)))
+}
+
+func TestValueRulesFor(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+	assert.Equal(valueRules{}, valueRulesFor(extended, "a", "b"))
+	assert.Equal(valueRules{extended: true, maxLen: 63}, valueRulesFor(extended, "a", "b c"))
+	assert.Equal(valueRules{maxLen: 100}, valueRulesFor(Options{MaxValueLen: 100}, strings.Repeat("a", 64)))
+}
+
+func TestValueRulesUnion(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(valueRules{}, valueRules{}.union(valueRules{}))
+	assert.Equal(valueRules{extended: true, maxLen: 63}, valueRules{}.union(valueRules{extended: true, maxLen: 10}))
+	assert.Equal(valueRules{maxLen: 100}, valueRules{maxLen: 100}.union(valueRules{}))
+}
+
+func TestValidateFollowsDialect(t *testing.T) {
+	assert := assert.New(t)
+
+	extended := Options{Dialect: DialectExtended}
+	for _, query := range []string{`x == "a b"`, `x != "a b"`, `x in ("a b", c)`, `x notin ("a b")`} {
+		selector, err := ParseWithOptions(query, extended)
+		assert.Nil(err, query)
+		assert.Nil(selector.Validate(), query)
+		assert.Nil(Negate(selector).Validate(), query)
+	}
+
+	selector, err := ParseWithOptions(`x != "a b", x notin (c), y in ("d e"), y in ("d e", f)`, extended)
+	assert.Nil(err)
+	assert.Nil(Simplify(selector).Validate())
+
+	long := strings.Repeat("a", 80)
+	selector, err = ParseWithOptions("x == "+long, Options{MaxValueLen: 100})
+	assert.Nil(err)
+	assert.Nil(selector.Validate())
+
+	selector, err = FromRequirements([]Requirement{{Key: "x", Operator: OpIn, Values: []string{"a b"}}}, extended)
+	assert.Nil(err)
+	assert.Nil(selector.Validate())
+
+	assert.True(errors.Is(Equals{Key: "x", Value: "a b"}.Validate(), ErrKeyInvalidCharacter), "constructed selectors use the kubernetes rules")
+}
@@ -0,0 +1,113 @@
+package selector
+
+// valueSet is the set of states a requirement on a single key matches:
+// the present values in `values`, or not in them if `negated`, and the absent key if `absent`.
+type valueSet struct {
+	key     string
+	values  []string
+	negated bool
+	absent  bool
+	rules   valueRules
+}
+
+// toValueSet returns the value set of a requirement on a single key, if it has one.
+func toValueSet(s Selector) (valueSet, bool) {
+	switch typed := s.(type) {
+	case HasKey:
+		return valueSet{key: string(typed), negated: true}, true
+	case NotHasKey:
+		return valueSet{key: string(typed), absent: true}, true
+	case Equals:
+		return valueSet{key: typed.Key, values: []string{typed.Value}, rules: typed.rules}, true
+	case NotEquals:
+		return valueSet{key: typed.Key, values: []string{typed.Value}, negated: true, absent: true, rules: typed.rules}, true
+	case In:
+		return valueSet{key: typed.Key, values: typed.Values, absent: !typed.RequireKey, rules: typed.rules}, true
+	case NotIn:
+		return valueSet{key: typed.Key, values: typed.Values, negated: true, absent: true, rules: typed.rules}, true
+	}
+	return valueSet{}, false
+}
+
+// union returns the states either value set matches.
+func (v valueSet) union(other valueSet) valueSet {
+	output := valueSet{key: v.key, absent: v.absent || other.absent, rules: v.rules.union(other.rules)}
+	switch {
+	case !v.negated && !other.negated:
+		output.values = appendUnique(v.values, other.values...)
+	case v.negated && other.negated:
+		output.negated = true
+		output.values = intersectValues(v.values, other.values)
+	case v.negated:
+		output.negated = true
+		output.values = subtractValues(v.values, other.values)
+	default:
+		output.negated = true
+		output.values = subtractValues(other.values, v.values)
+	}
+	return output
+}
+
+// selector returns a conjunction of requirements matching the value set.
+// An `In` is only used with the key-absent behavior of an `In` it came from, so the result prints
+// and parses back the same way; it returns false if no such conjunction exists.
+// A value set matching every state of the key gives an empty `And`, which matches everything.
+func (v valueSet) selector(strictIn, compatibleIn bool) (Selector, bool) {
+	if v.negated {
+		var s Selector = NotIn{Key: v.key, Values: v.values, rules: v.rules}
+		switch len(v.values) {
+		case 0:
+			if v.absent {
+				return And{}, true
+			}
+			return HasKey(v.key), true
+		case 1:
+			s = NotEquals{Key: v.key, Value: v.values[0], rules: v.rules}
+		}
+		if v.absent {
+			return s, true
+		}
+		return And{s, HasKey(v.key)}, true
+	}
+
+	if v.absent {
+		switch {
+		case len(v.values) == 0:
+			return NotHasKey(v.key), true
+		case compatibleIn:
+			return In{Key: v.key, Values: v.values, rules: v.rules}, true
+		}
+		return nil, false
+	}
+	switch {
+	case len(v.values) == 0:
+		return nil, false
+	case len(v.values) == 1:
+		return Equals{Key: v.key, Value: v.values[0], rules: v.rules}, true
+	case strictIn:
+		return In{Key: v.key, Values: v.values, RequireKey: true, rules: v.rules}, true
+	}
+	return And{In{Key: v.key, Values: v.values, rules: v.rules}, HasKey(v.key)}, true
+}
+
+// intersectValues returns the values in both slices, in the order of the first.
+func intersectValues(values, others []string) []string {
+	var output []string
+	for _, value := range values {
+		if containsString(others, value) {
+			output = append(output, value)
+		}
+	}
+	return output
+}
+
+// subtractValues returns the values not in the others, in order.
+func subtractValues(values, others []string) []string {
+	var output []string
+	for _, value := range values {
+		if !containsString(others, value) {
+			output = append(output, value)
+		}
+	}
+	return output
+}
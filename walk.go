@@ -0,0 +1,72 @@
+package selector
+
+// Visitor is called by `Walk` for each selector in a tree.
+// If `Visit` returns a non-nil visitor w, `Walk` visits each of the selector's children with w,
+// followed by a call of `w.Visit(nil)`.
+type Visitor interface {
+	Visit(s Selector) (w Visitor)
+}
+
+// Walk traverses a selector tree in depth first order, starting with `v.Visit(s)`.
+// Combinations (`And`, `Or`, `Not`, `Fold` and any custom selector implementing `Parent`) have their
+// children visited; every other selector is a leaf.
+func Walk(s Selector, v Visitor) {
+	if s == nil {
+		return
+	}
+	if v = v.Visit(s); v == nil {
+		return
+	}
+	if parent, isParent := s.(Parent); isParent {
+		for _, child := range parent.Children() {
+			Walk(child, v)
+		}
+	}
+	v.Visit(nil)
+}
+
+// inspector is a visitor for a function.
+type inspector func(Selector) bool
+
+// Visit implements Visitor.
+func (f inspector) Visit(s Selector) Visitor {
+	if f(s) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses a selector tree in depth first order, calling f for each selector
+// and then f(nil) after a selector's children. If f returns false the selector's children are skipped.
+func Inspect(s Selector, f func(Selector) bool) {
+	Walk(s, inspector(f))
+}
+
+// Rewrite returns a copy of a selector tree with f applied to every selector, children first,
+// so f sees combinations with their children already rewritten.
+// If f returns nil for a child the child is removed from its parent, and a parent left without children
+// is removed too, e.g. rewriting `x || y` with every `HasKey` removed returns nil rather than an `Or`
+// that matches nothing. Combinations that were empty to begin with, e.g. `And{}`, are kept.
+// The input is not modified.
+func Rewrite(s Selector, f func(Selector) Selector) Selector {
+	if s == nil {
+		return nil
+	}
+	if parent, isParent := s.(Parent); isParent {
+		children := parent.Children()
+		if len(children) == 0 {
+			return f(s)
+		}
+		rewritten := make([]Selector, 0, len(children))
+		for _, child := range children {
+			if child = Rewrite(child, f); child != nil {
+				rewritten = append(rewritten, child)
+			}
+		}
+		s = parent.WithChildren(rewritten)
+		if s == nil {
+			return nil
+		}
+	}
+	return f(s)
+}
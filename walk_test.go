@@ -0,0 +1,130 @@
+package selector
+
+import (
+	"strings"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+// anyOf is a custom combination selector used to test traversal of `Parent` implementations.
+type anyOf struct {
+	selectors []Selector
+}
+
+func (a anyOf) Matches(labels Labels) bool         { return Or(a.selectors).Matches(labels) }
+func (a anyOf) Validate() error                    { return Or(a.selectors).Validate() }
+func (a anyOf) String() string                     { return "any(" + And(a.selectors).String() + ")" }
+func (a anyOf) Children() []Selector               { return a.selectors }
+func (a anyOf) WithChildren(c []Selector) Selector { return anyOf{selectors: c} }
+
+// recorder records the selectors it visits.
+type recorder struct {
+	visited []string
+}
+
+func (r *recorder) Visit(s Selector) Visitor {
+	if s == nil {
+		r.visited = append(r.visited, "end")
+		return nil
+	}
+	r.visited = append(r.visited, s.String())
+	return r
+}
+
+func TestWalk(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := And{HasKey("x"), Or{Equals{Key: "y", Value: "a"}, Not{Selector: HasKey("z")}}}
+	r := &recorder{}
+	Walk(selector, r)
+	assert.Equal([]string{
+		"x, (y == a || !z)",
+		"x", "end",
+		"y == a || !z",
+		"y == a", "end",
+		"!z",
+		"z", "end",
+		"end",
+		"end",
+		"end",
+	}, r.visited)
+
+	Walk(nil, r)
+}
+
+func TestInspect(t *testing.T) {
+	assert := assert.New(t)
+
+	selector := And{HasKey("x"), anyOf{selectors: []Selector{Equals{Key: "y", Value: "a"}, Fold{Selector: HasKey("w")}}}, Not{Selector: HasKey("z")}}
+	var keys []string
+	Inspect(selector, func(s Selector) bool {
+		switch typed := s.(type) {
+		case Not:
+			return false
+		case HasKey:
+			keys = append(keys, string(typed))
+		case Equals:
+			keys = append(keys, typed.Key)
+		}
+		return true
+	})
+	assert.Equal([]string{"x", "y", "w"}, keys)
+}
+
+func TestRewrite(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseWithOptions("app == web, !(tier in (a, b) || old), x", Options{Dialect: DialectExtended})
+	assert.Nil(err)
+
+	renamed := Rewrite(selector, func(s Selector) Selector {
+		switch typed := s.(type) {
+		case Equals:
+			return Equals{Key: strings.ToUpper(typed.Key), Value: typed.Value}
+		case In:
+			return In{Key: "example.com/" + typed.Key, Values: typed.Values}
+		case HasKey:
+			if typed == "old" {
+				return nil
+			}
+		}
+		return s
+	})
+	assert.Equal("APP == web, !(example.com/tier in (a, b)), x", renamed.String())
+	assert.Equal("app == web, !(tier in (a, b) || old), x", selector.String())
+}
+
+func TestRewriteRemoves(t *testing.T) {
+	assert := assert.New(t)
+
+	remove := func(s Selector) Selector {
+		if _, isHasKey := s.(HasKey); isHasKey {
+			return nil
+		}
+		return s
+	}
+	assert.Nil(Rewrite(HasKey("x"), remove))
+	assert.Nil(Rewrite(Not{Selector: HasKey("x")}, remove))
+	assert.Equal(And{NotHasKey("y")}, Rewrite(And{HasKey("x"), NotHasKey("y")}, remove))
+	assert.Equal(anyOf{selectors: []Selector{NotHasKey("y")}}, Rewrite(anyOf{selectors: []Selector{HasKey("x"), NotHasKey("y")}}, remove))
+	assert.Nil(Rewrite(nil, remove))
+	assert.Nil(Rewrite(Or{HasKey("x"), HasKey("y")}, remove))
+	assert.Nil(Rewrite(And{HasKey("x")}, remove))
+	assert.Equal(And{}, Rewrite(And{}, remove))
+}
+
+func TestParentChildren(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]Selector{HasKey("x")}, Not{Selector: HasKey("x")}.Children())
+	assert.Nil(Not{}.Children())
+	assert.Equal(Not{Selector: And{HasKey("x"), HasKey("y")}}, Not{}.WithChildren([]Selector{HasKey("x"), HasKey("y")}))
+	assert.Equal(Fold{Selector: HasKey("x"), Keys: true}, Fold{Keys: true}.WithChildren([]Selector{HasKey("x")}))
+	assert.Nil(Fold{}.WithChildren(nil))
+	assert.Equal(Or{HasKey("x")}, Or{}.WithChildren([]Selector{HasKey("x")}))
+	assert.Equal(And{HasKey("x")}, And{}.WithChildren([]Selector{HasKey("x")}))
+	assert.Nil(And{}.WithChildren(nil))
+	assert.Nil(Or{}.WithChildren(nil))
+	assert.Nil(Not{}.WithChildren(nil))
+}
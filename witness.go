@@ -0,0 +1,73 @@
+package selector
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Witness returns a minimal label set that matches a selector, with the fewest labels of any the solver finds.
+// Keys and values pass `CheckKey` and `CheckValue`. If the selector can't match a valid label set the error
+// is `ErrNoWitness`, and names the conflicting requirements where there are some.
+// Selector types from other packages return `ErrUnsupportedSelector`, and patterns the solver can't decide `ErrUndecidable`.
+func Witness(s Selector) (Labels, error) {
+	return findLabels(s)
+}
+
+// Counterexample returns a minimal label set that a selector doesn't match, as `Witness` does for its negation.
+func Counterexample(s Selector) (Labels, error) {
+	return findLabels(Not{Selector: s})
+}
+
+// findLabels returns the smallest valid label set found across the clauses of a selector.
+func findLabels(s Selector) (Labels, error) {
+	clauses, err := toClauses(s, false)
+	if err != nil {
+		return nil, err
+	}
+	var best Labels
+	var invalid error
+	for _, c := range clauses {
+		result, labels, _ := c.solve()
+		if result != satisfiable {
+			continue
+		}
+		if err := checkLabels(labels); err != nil {
+			if invalid == nil {
+				invalid = err
+			}
+			continue
+		}
+		if best == nil || len(labels) < len(best) {
+			best = labels
+		}
+	}
+	if best != nil {
+		return best, nil
+	}
+	if invalid != nil {
+		return nil, invalid
+	}
+	result, _, contradiction, _ := solve(s)
+	if result == undecided {
+		return nil, ErrUndecidable
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoWitness, contradiction)
+}
+
+// checkLabels returns an `ErrNoWitness` error for the first invalid key or value, in key order.
+func checkLabels(labels Labels) error {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := CheckKey(key); err != nil {
+			return fmt.Errorf("%w: key %q: %w", ErrNoWitness, key, err)
+		}
+		if err := CheckValue(labels[key]); err != nil {
+			return fmt.Errorf("%w: %s value %q: %w", ErrNoWitness, key, labels[key], err)
+		}
+	}
+	return nil
+}
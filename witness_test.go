@@ -0,0 +1,82 @@
+package selector
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	assert "github.com/blendlabs/go-assert"
+)
+
+func TestWitness(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct {
+		query          string
+		witness        Labels
+		counterexample Labels
+	}{
+		{"app=web", Labels{"app": "web"}, Labels{}},
+		{"app in (web, api)", Labels{}, Labels{"app": "0"}},
+		{"app notin (web, api)", Labels{}, Labels{"app": "web"}},
+		{"app, env != prod", Labels{"app": "0"}, Labels{}},
+		{"!app", Labels{}, Labels{"app": "0"}},
+		{"app=web || env=prod", Labels{"app": "web"}, Labels{}},
+		{"replicas > -3", Labels{"replicas": "0"}, Labels{}},
+		{"replicas >= 4, replicas < 5", Labels{"replicas": "4"}, Labels{}},
+		{"name ~= web-*", Labels{"name": "web-x"}, Labels{}},
+	}
+	for _, c := range cases {
+		selector, err := ParseWithOptions(c.query, Options{Dialect: DialectExtended})
+		assert.Nil(err, c.query)
+
+		witness, err := Witness(selector)
+		assert.Nil(err, c.query)
+		assert.Equal(c.witness, witness, c.query)
+		assert.True(selector.Matches(witness), c.query)
+
+		counterexample, err := Counterexample(selector)
+		assert.Nil(err, c.query)
+		assert.Equal(c.counterexample, counterexample, c.query)
+		assert.False(selector.Matches(counterexample), c.query)
+	}
+}
+
+func TestWitnessErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, _ := Parse("x=a,x=b")
+	_, err := Witness(selector)
+	assert.True(errors.Is(err, ErrNoWitness))
+	assert.Equal(`no label set found: x == a, x == b can't all match "x"`, err.Error())
+
+	selector, _ = ParseWithOptions("x in (a, b) || x notin (a, b)", Options{Dialect: DialectExtended})
+	_, err = Counterexample(selector)
+	assert.True(errors.Is(err, ErrNoWitness))
+
+	selector, _ = ParseWithOptions("replicas < 0", Options{Dialect: DialectExtended})
+	_, err = Witness(selector)
+	assert.True(errors.Is(err, ErrNoWitness))
+	assert.True(errors.Is(err, ErrKeyInvalidCharacter))
+
+	_, err = Witness(Fold{Selector: HasKey("x")})
+	assert.True(errors.Is(err, ErrUnsupportedSelector))
+}
+
+func TestWitnessValid(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(8))
+	for index := 0; index < 512; index++ {
+		selector, err := Parse(generateSelector(r))
+		assert.Nil(err)
+		if witness, err := Witness(selector); err == nil {
+			assert.True(selector.Matches(witness), selector, " ", witness)
+			assert.Nil(checkLabels(witness))
+		}
+		if counterexample, err := Counterexample(selector); err == nil {
+			assert.False(selector.Matches(counterexample), selector, " ", counterexample)
+			assert.Nil(checkLabels(counterexample))
+		}
+	}
+}